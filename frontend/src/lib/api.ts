@@ -1,4 +1,4 @@
-import type { Node, Job, Partition, Alert, ClusterStatus } from '@/types'
+import type { Node, Job, Partition, Alert, ClusterStatus, Inventory } from '@/types'
 
 const API_BASE = '/api/v1'
 
@@ -23,6 +23,10 @@ export async function getClusterStatus(): Promise<ClusterStatus> {
   return fetchAPI<ClusterStatus>('/cluster/status')
 }
 
+export async function getInventory(): Promise<Inventory> {
+  return fetchAPI<Inventory>('/cluster/inventory')
+}
+
 // Nodes
 export async function getNodes(): Promise<{ nodes: Node[]; total: number }> {
   return fetchAPI('/cluster/nodes')
@@ -40,6 +44,14 @@ export async function resumeNode(id: string): Promise<{ message: string }> {
   return fetchAPI(`/cluster/nodes/${id}/resume`, { method: 'POST' })
 }
 
+export async function cordonNode(id: string): Promise<{ node: string; schedulable: boolean }> {
+  return fetchAPI(`/cluster/nodes/${id}/cordon`, { method: 'POST' })
+}
+
+export async function uncordonNode(id: string): Promise<{ node: string; schedulable: boolean }> {
+  return fetchAPI(`/cluster/nodes/${id}/uncordon`, { method: 'POST' })
+}
+
 // Jobs
 export async function getJobs(params?: {
   state?: string
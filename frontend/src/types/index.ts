@@ -59,6 +59,16 @@ export interface ClusterStatus {
   gpus_active: number
 }
 
+export interface Inventory {
+  gpus_by_model: Record<string, number>
+  gpus_total: number
+  gpu_memory_total_gib: number
+  gpu_max_power_total_w: number
+  nodes_by_type: Record<string, number>
+  nodes_total: number
+  generated_at: string
+}
+
 export interface MetricData {
   metric: Record<string, string>
   values: [number, string][]
@@ -4,7 +4,7 @@ import uuid
 import logging
 from datetime import datetime
 
-from fastapi import APIRouter, HTTPException
+from fastapi import APIRouter, HTTPException, Response
 from fastapi.responses import StreamingResponse
 
 from models import (
@@ -21,6 +21,9 @@ logger = logging.getLogger(__name__)
 
 router = APIRouter()
 
+# Set to the number of old turns dropped when a conversation hit its limits
+TRUNCATED_HEADER = "X-Conversation-Truncated"
+
 
 @router.get("/health", response_model=HealthResponse)
 async def health_check():
@@ -36,7 +39,7 @@ async def health_check():
 
 
 @router.post("/chat", response_model=ChatResponse)
-async def chat(request: ChatRequest):
+async def chat(request: ChatRequest, response: Response):
     """Send a chat message and get a response."""
     conversation_id = request.conversation_id or str(uuid.uuid4())
 
@@ -53,14 +56,16 @@ async def chat(request: ChatRequest):
             logger.warning(f"Failed to fetch context: {e}")
 
     try:
-        response = await ollama_service.chat(
+        reply, dropped = await ollama_service.chat(
             message=request.message,
             conversation_id=conversation_id,
             context=context
         )
+        if dropped:
+            response.headers[TRUNCATED_HEADER] = str(dropped)
 
         return ChatResponse(
-            message=response,
+            message=reply,
             conversation_id=conversation_id,
             context_used=context_sources,
             model=ollama_service.model,
@@ -85,22 +90,27 @@ async def chat_stream(request: ChatRequest):
         except Exception as e:
             logger.warning(f"Failed to fetch context: {e}")
 
+    stream, dropped = ollama_service.chat_stream(
+        message=request.message,
+        conversation_id=conversation_id,
+        context=context
+    )
+
     async def generate():
         try:
-            async for chunk in ollama_service.chat_stream(
-                message=request.message,
-                conversation_id=conversation_id,
-                context=context
-            ):
+            async for chunk in stream:
                 yield chunk
         except Exception as e:
             logger.error(f"Stream error: {e}")
             yield f"\n\nError: {str(e)}"
 
+    headers = {"X-Conversation-ID": conversation_id}
+    if dropped:
+        headers[TRUNCATED_HEADER] = str(dropped)
     return StreamingResponse(
         generate(),
         media_type="text/plain",
-        headers={"X-Conversation-ID": conversation_id}
+        headers=headers
     )
 
 
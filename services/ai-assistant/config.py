@@ -25,6 +25,11 @@ class Settings(BaseSettings):
     max_alert_history: int = 20
     max_job_history: int = 50
 
+    # Conversation limits; oldest turns are dropped first when exceeded
+    max_conversation_messages: int = 20
+    max_conversation_chars: int = 32000
+    summarize_dropped_turns: bool = False
+
     # Response configuration
     max_tokens: int = 2048
     temperature: float = 0.7
@@ -11,6 +11,10 @@ from prompts import SYSTEM_PROMPT, INVESTIGATION_PROMPT, build_context_prompt
 
 logger = logging.getLogger(__name__)
 
+# Prefix of the synthesized turn that stands in for dropped history
+DROPPED_SUMMARY_PREFIX = "Earlier in this conversation"
+DROPPED_SUMMARY_ASKED = "the user asked: "
+
 
 class OllamaService:
     """Service for interacting with Ollama LLM."""
@@ -48,14 +52,60 @@ class OllamaService:
             self.conversations[conversation_id] = []
         return self.conversations[conversation_id]
 
-    def _trim_conversation(self, messages: list[dict], max_messages: int = 20) -> list[dict]:
-        """Trim conversation history to prevent context overflow."""
-        if len(messages) <= max_messages:
-            return messages
-        # Keep system message (if first) and recent messages
-        if messages and messages[0].get("role") == "system":
-            return [messages[0]] + messages[-(max_messages - 1):]
-        return messages[-max_messages:]
+    def _trim_conversation(self, messages: list[dict]) -> tuple[list[dict], int]:
+        """Drop the oldest turns until the conversation fits the configured
+        message and character limits.
+
+        Leading system messages (prompt and cluster context) and the newest
+        turn are always kept. Returns the trimmed list and how many turns
+        were dropped.
+        """
+        head = 0
+        while head < len(messages) and messages[head].get("role") == "system" and \
+                not messages[head].get("content", "").startswith(DROPPED_SUMMARY_PREFIX):
+            head += 1
+        system, turns = messages[:head], messages[head:]
+
+        # A summary from an earlier trim is folded into the new one
+        earlier = []
+        if turns and turns[0].get("role") == "system" and \
+                turns[0].get("content", "").startswith(DROPPED_SUMMARY_PREFIX):
+            earlier = [turns.pop(0)]
+
+        dropped = []
+        while len(turns) > 1:
+            # The summary turn, once there is one, takes a message slot
+            summary = 1 if settings.summarize_dropped_turns and (earlier or dropped) else 0
+            count = len(system) + summary + len(turns)
+            chars = sum(len(m.get("content", "")) for m in system + earlier + turns)
+            if count <= settings.max_conversation_messages and chars <= settings.max_conversation_chars:
+                break
+            dropped.append(turns.pop(0))
+
+        if not dropped:
+            return system + earlier + turns, 0
+        summary = None
+        if settings.summarize_dropped_turns:
+            summary = self._summarize_dropped(earlier + dropped)
+        return system + ([summary] if summary else []) + turns, len(dropped)
+
+    def _summarize_dropped(self, dropped: list[dict]) -> Optional[dict]:
+        """Synthesize a short system turn noting what the user asked in the
+        dropped part of the conversation, or None if there is nothing to note."""
+        questions = []
+        for m in dropped:
+            if m.get("role") == "user":
+                text = " ".join(m.get("content", "").split())
+                questions.append(text[:80] + ("..." if len(text) > 80 else ""))
+            elif m.get("content", "").startswith(DROPPED_SUMMARY_PREFIX):
+                # Carry forward what an earlier summary already listed
+                _, _, listed = m["content"].partition(DROPPED_SUMMARY_ASKED)
+                questions.extend(q for q in listed.split("; ") if q)
+        if not questions:
+            return None
+        content = f"{DROPPED_SUMMARY_PREFIX}, omitted to fit the context window, {DROPPED_SUMMARY_ASKED}"
+        content += "; ".join(questions[-5:])
+        return {"role": "system", "content": content}
 
     async def chat(
         self,
@@ -63,8 +113,9 @@ class OllamaService:
         conversation_id: str,
         context: Optional[dict] = None,
         stream: bool = False
-    ) -> str:
-        """Send a chat message and get a response."""
+    ) -> tuple[str, int]:
+        """Send a chat message and get a response, along with the number of
+        old turns dropped to fit the conversation limits."""
         messages = self._get_conversation(conversation_id)
 
         # Add system prompt if new conversation
@@ -100,7 +151,7 @@ class OllamaService:
         })
 
         # Trim if needed
-        messages = self._trim_conversation(messages)
+        messages, dropped = self._trim_conversation(messages)
         self.conversations[conversation_id] = messages
 
         try:
@@ -121,19 +172,20 @@ class OllamaService:
                 "content": assistant_message
             })
 
-            return assistant_message
+            return assistant_message, dropped
 
         except Exception as e:
             logger.error(f"Chat error: {e}")
             raise
 
-    async def chat_stream(
+    def chat_stream(
         self,
         message: str,
         conversation_id: str,
         context: Optional[dict] = None
-    ) -> AsyncGenerator[str, None]:
-        """Stream a chat response."""
+    ) -> tuple[AsyncGenerator[str, None], int]:
+        """Stream a chat response. The history is updated and trimmed before
+        returning, so the number of dropped turns is known up front."""
         messages = self._get_conversation(conversation_id)
 
         if not messages:
@@ -154,9 +206,12 @@ class OllamaService:
             "content": message
         })
 
-        messages = self._trim_conversation(messages)
+        messages, dropped = self._trim_conversation(messages)
         self.conversations[conversation_id] = messages
+        return self._stream_reply(messages), dropped
 
+    async def _stream_reply(self, messages: list[dict]) -> AsyncGenerator[str, None]:
+        """Stream the model's reply and append it to the history."""
         full_response = ""
         try:
             async for chunk in await self.client.chat(
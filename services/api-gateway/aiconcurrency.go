@@ -0,0 +1,76 @@
+package main
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AI concurrency state; aiSlots holds one token per in-flight AI call and
+// is nil when AI_MAX_CONCURRENCY is 0
+var (
+	aiSlots        chan struct{}
+	aiQueueTimeout time.Duration
+)
+
+// initAIConcurrency sizes the AI semaphore. Calls beyond max wait up to
+// queueTimeout for a slot, or are rejected at once when it is zero.
+func initAIConcurrency(max int, queueTimeout time.Duration) {
+	aiQueueTimeout = queueTimeout
+	if max == 0 {
+		aiSlots = nil
+		slog.Warn("AI concurrency limit disabled")
+		return
+	}
+	aiSlots = make(chan struct{}, max)
+	slog.Info("AI concurrency limit initialized", "max", max, "queue_timeout", queueTimeout.String())
+}
+
+// acquireAISlot takes a slot, waiting up to aiQueueTimeout for one
+func acquireAISlot() bool {
+	select {
+	case aiSlots <- struct{}{}:
+		return true
+	default:
+	}
+	if aiQueueTimeout == 0 {
+		return false
+	}
+	timer := time.NewTimer(aiQueueTimeout)
+	defer timer.Stop()
+	select {
+	case aiSlots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// limitAIConcurrency bounds how many calls to the AI assistant run at once,
+// separately from the per-IP rate limit, so slow LLM calls can't use up the
+// provider's rate limit or the gateway's connections. Saturated calls get a
+// 429 with code AI_CONCURRENCY_LIMIT.
+func limitAIConcurrency(c *fiber.Ctx) error {
+	if aiSlots == nil {
+		return c.Next()
+	}
+	if !acquireAISlot() {
+		aiRejectedTotal.Inc()
+		slog.Warn("AI concurrency limit reached", "path", c.Path(), "max", cap(aiSlots))
+		retryAfter := max(aiQueueTimeout, time.Second)
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retryAfter.Seconds())))
+		return respond(c, fiber.StatusTooManyRequests, fiber.Map{
+			"error":       "AI assistant is at capacity",
+			"code":        "AI_CONCURRENCY_LIMIT",
+			"retry_after": retryAfter.String(),
+		})
+	}
+	aiInFlight.Inc()
+	defer func() {
+		aiInFlight.Dec()
+		<-aiSlots
+	}()
+	return c.Next()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// States an alert change event can report, also used as the SSE event name
+const (
+	alertStateFiring         = "firing"
+	alertStateResolved       = "resolved"
+	alertStateAcknowledged   = "acknowledged"
+	alertStateUnacknowledged = "unacknowledged"
+)
+
+// alertEventBuffer is how many events a subscriber may fall behind by
+// before further events are dropped for it
+const alertEventBuffer = 64
+
+// alertStreamKeepalive is how often an SSE comment is sent on a quiet alert
+// stream, so idle proxies don't close it
+const alertStreamKeepalive = 15 * time.Second
+
+// AlertEvent is one change to the alert store: an alert starting to fire
+// (new or re-fired), resolving, or being acknowledged or unacknowledged
+type AlertEvent struct {
+	Fingerprint string    `json:"fingerprint"`
+	AlertName   string    `json:"alertname"`
+	Severity    string    `json:"severity"`
+	State       string    `json:"state"`
+	Time        time.Time `json:"time"`
+}
+
+// Alert event subscribers, one buffered channel per open stream
+var (
+	alertSubscribers      = make(map[chan AlertEvent]struct{})
+	alertSubscribersMutex = &sync.Mutex{}
+)
+
+// subscribeAlertEvents registers a new subscriber
+func subscribeAlertEvents() chan AlertEvent {
+	events := make(chan AlertEvent, alertEventBuffer)
+	alertSubscribersMutex.Lock()
+	alertSubscribers[events] = struct{}{}
+	alertStreamClients.Set(float64(len(alertSubscribers)))
+	alertSubscribersMutex.Unlock()
+	return events
+}
+
+// unsubscribeAlertEvents removes a subscriber; its channel is left open
+// since a publish may still hold it
+func unsubscribeAlertEvents(events chan AlertEvent) {
+	alertSubscribersMutex.Lock()
+	delete(alertSubscribers, events)
+	alertStreamClients.Set(float64(len(alertSubscribers)))
+	alertSubscribersMutex.Unlock()
+}
+
+// publishAlertEvent sends a change for stored to every subscriber. It never
+// blocks: a subscriber whose buffer is full misses the event, so a slow
+// stream can't hold up the webhook.
+func publishAlertEvent(stored *StoredAlert, state string) {
+	event := AlertEvent{
+		Fingerprint: stored.Fingerprint,
+		AlertName:   stored.Labels["alertname"],
+		Severity:    normalizeSeverity(stored.Labels["severity"]),
+		State:       state,
+		Time:        time.Now().UTC(),
+	}
+
+	alertSubscribersMutex.Lock()
+	defer alertSubscribersMutex.Unlock()
+	for events := range alertSubscribers {
+		select {
+		case events <- event:
+		default:
+			alertEventsDroppedTotal.Inc()
+		}
+	}
+}
+
+// streamAlertEvents streams alert changes as server-sent events named after
+// the new state, each carrying an AlertEvent. A keep-alive comment is sent
+// when nothing has changed for alertStreamKeepalive.
+func streamAlertEvents(c *fiber.Ctx) error {
+	events := subscribeAlertEvents()
+
+	conn := c.Context().Conn()
+	c.Status(fiber.StatusOK)
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set("X-Accel-Buffering", "no") // Keep reverse proxies from holding events back
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribeAlertEvents(events)
+
+		// An opening comment gets the headers to the client straight away
+		conn.SetWriteDeadline(time.Now().Add(logStreamWriteTimeout))
+		if _, err := w.WriteString(": connected\n\n"); err != nil || w.Flush() != nil {
+			return
+		}
+
+		keepalive := time.NewTicker(alertStreamKeepalive)
+		defer keepalive.Stop()
+		for {
+			select {
+			case event := <-events:
+				data, _ := json.Marshal(event)
+				if err := writeEvent(w, conn, event.State, data); err != nil {
+					slog.Debug("Alert stream client went away", "error", err)
+					return
+				}
+				keepalive.Reset(alertStreamKeepalive)
+			case <-keepalive.C:
+				conn.SetWriteDeadline(time.Now().Add(logStreamWriteTimeout))
+				if _, err := w.WriteString(": keepalive\n\n"); err != nil || w.Flush() != nil {
+					slog.Debug("Alert stream client went away")
+					return
+				}
+			}
+		}
+	})
+	return nil
+}
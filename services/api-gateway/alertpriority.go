@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Each priority component is scaled to 0-1; these are the points at which
+// firing duration and node spread stop adding to the score
+const (
+	priorityDurationCap = time.Hour
+	priorityNodesCap    = 10
+)
+
+const priorityFormula = "priority = 100 * (w_severity*severity + w_duration*duration + w_nodes*nodes) / (w_severity + w_duration + w_nodes)"
+
+// PriorityWeights weight the components of an alert's priority score
+type PriorityWeights struct {
+	Severity float64 `json:"severity"`
+	Duration float64 `json:"duration"`
+	Nodes    float64 `json:"nodes"`
+}
+
+var priorityWeights = PriorityWeights{Severity: 0.6, Duration: 0.25, Nodes: 0.15}
+
+// initAlertPriority parses a "severity=w,duration=w,nodes=w" spec. Omitted
+// components keep their default weight.
+func initAlertPriority(spec string) error {
+	weights := priorityWeights
+	var problems []error
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || weight < 0 {
+			problems = append(problems, fmt.Errorf("invalid weight %q, want name=non-negative number", entry))
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "severity":
+			weights.Severity = weight
+		case "duration":
+			weights.Duration = weight
+		case "nodes":
+			weights.Nodes = weight
+		default:
+			problems = append(problems, fmt.Errorf("unknown priority component %q (want severity, duration or nodes)", name))
+		}
+	}
+	if len(problems) == 0 && weights.Severity+weights.Duration+weights.Nodes == 0 {
+		problems = append(problems, errors.New("at least one priority weight must be positive"))
+	}
+	if len(problems) > 0 {
+		return errors.Join(problems...)
+	}
+
+	priorityWeights = weights
+	slog.Info("Alert priority weights initialized",
+		"severity", weights.Severity,
+		"duration", weights.Duration,
+		"nodes", weights.Nodes,
+	)
+	return nil
+}
+
+// alertNode returns the node an alert fires for, if it names one
+func alertNode(alert *StoredAlert) string {
+	if node := alert.Labels["node"]; node != "" {
+		return node
+	}
+	return alert.Labels["instance"]
+}
+
+// affectedNodes counts the distinct nodes each alertname is firing on
+func affectedNodes(alerts []*StoredAlert) map[string]int {
+	nodes := make(map[string]map[string]bool)
+	for _, alert := range alerts {
+		name := alert.Labels["alertname"]
+		if nodes[name] == nil {
+			nodes[name] = make(map[string]bool)
+		}
+		if node := alertNode(alert); node != "" {
+			nodes[name][node] = true
+		}
+	}
+
+	counts := make(map[string]int, len(nodes))
+	for name, set := range nodes {
+		counts[name] = len(set)
+	}
+	return counts
+}
+
+// alertPriority scores an alert from 0 to 100 using the configured weights
+func alertPriority(alert *StoredAlert, nodes int, now time.Time) float64 {
+	severity := 0.0
+	severityName := normalizeSeverity(alert.Labels["severity"])
+	for _, level := range severityLevels {
+		if level.Name == severityName {
+			severity = float64(len(severityLevels)-level.Priority+1) / float64(len(severityLevels))
+			break
+		}
+	}
+
+	started := alert.StartsAt
+	if started.IsZero() {
+		started = alert.FirstSeen
+	}
+	duration := math.Min(now.Sub(started).Seconds()/priorityDurationCap.Seconds(), 1)
+	duration = math.Max(duration, 0)
+
+	spread := math.Min(float64(nodes)/priorityNodesCap, 1)
+
+	w := priorityWeights
+	score := 100 * (w.Severity*severity + w.Duration*duration + w.Nodes*spread) /
+		(w.Severity + w.Duration + w.Nodes)
+	return math.Round(score*100) / 100
+}
+
+// priorityScoring documents the scoring formula in list responses
+func priorityScoring() fiber.Map {
+	return fiber.Map{
+		"formula": priorityFormula,
+		"weights": priorityWeights,
+		"components": fiber.Map{
+			"severity": "1 for the most urgent configured severity, falling linearly to 1/N for the least; 0 if unknown",
+			"duration": fmt.Sprintf("minutes firing divided by %.0f, capped at 1", priorityDurationCap.Minutes()),
+			"nodes":    fmt.Sprintf("distinct nodes firing the same alertname divided by %d, capped at 1", priorityNodesCap),
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestListAlerts_Sort(t *testing.T) {
+	app := newTestApp(t, "http://127.0.0.1:1")
+
+	now := time.Now()
+	err := storeAlerts(context.Background(), []Alert{
+		{
+			Status:      "firing",
+			Labels:      Labels{"alertname": "GPUOverheat", "severity": "critical"},
+			StartsAt:    now.Add(-2 * time.Hour),
+			Fingerprint: "old-critical",
+		},
+		{
+			Status:      "firing",
+			Labels:      Labels{"alertname": "DiskFilling", "severity": "info"},
+			StartsAt:    now.Add(-time.Minute),
+			Fingerprint: "new-info",
+		},
+	}, now)
+	if err != nil {
+		t.Fatalf("storeAlerts: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantSort string
+		want     []string
+	}{
+		{"newest first by default", "", alertSortNewest, []string{"new-info", "old-critical"}},
+		{"explicit newest", "?sort=newest", alertSortNewest, []string{"new-info", "old-critical"}},
+		{"priority opt-in", "?sort=priority", alertSortPriority, []string{"old-critical", "new-info"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, "/api/v1/alerts"+tt.query)
+			if status != http.StatusOK {
+				t.Fatalf("status %d, body %s", status, body)
+			}
+			var resp struct {
+				Alerts []struct {
+					Fingerprint string `json:"fingerprint"`
+				} `json:"alerts"`
+				Sort string `json:"sort"`
+			}
+			if err := json.Unmarshal([]byte(body), &resp); err != nil {
+				t.Fatalf("decoding %s: %v", body, err)
+			}
+			got := make([]string, len(resp.Alerts))
+			for i, alert := range resp.Alerts {
+				got[i] = alert.Fingerprint
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order %v, want %v", got, tt.want)
+			}
+			if resp.Sort != tt.wantSort {
+				t.Errorf("sort echoed as %q, want %q", resp.Sort, tt.wantSort)
+			}
+		})
+	}
+
+	if status, body := get(t, app, "/api/v1/alerts?sort=oldest"); status != http.StatusBadRequest {
+		t.Errorf("sort=oldest: status %d, body %s, want 400", status, body)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// alertStoreKey is the Redis hash holding active alerts, one field per
+// fingerprint with the StoredAlert as JSON
+const alertStoreKey = "pulse:alerts"
+
+// alertStoreTimeout bounds each Redis call made for the alert store, within
+// any deadline the caller's context already carries
+const alertStoreTimeout = 2 * time.Second
+
+// AlertStore holds the active alerts by fingerprint. Entries are copies, so
+// a change takes effect when the alert is Put back; callers serialize
+// read-modify-write sequences with alertStoreMutex. Handlers pass
+// c.UserContext(), so the request deadline bounds store calls.
+type AlertStore interface {
+	Get(ctx context.Context, fingerprint string) (*StoredAlert, bool, error)
+	Put(ctx context.Context, alert *StoredAlert) error
+	Delete(ctx context.Context, fingerprint string) error
+	List(ctx context.Context) ([]*StoredAlert, error)
+}
+
+// memoryAlertStore keeps alerts in process memory; they are lost on restart
+type memoryAlertStore struct {
+	alerts map[string]*StoredAlert
+}
+
+func newMemoryAlertStore() *memoryAlertStore {
+	return &memoryAlertStore{alerts: make(map[string]*StoredAlert)}
+}
+
+func (s *memoryAlertStore) Get(_ context.Context, fingerprint string) (*StoredAlert, bool, error) {
+	stored, ok := s.alerts[fingerprint]
+	if !ok {
+		return nil, false, nil
+	}
+	copied := *stored
+	return &copied, true, nil
+}
+
+func (s *memoryAlertStore) Put(_ context.Context, alert *StoredAlert) error {
+	copied := *alert
+	s.alerts[alert.Fingerprint] = &copied
+	return nil
+}
+
+func (s *memoryAlertStore) Delete(_ context.Context, fingerprint string) error {
+	delete(s.alerts, fingerprint)
+	return nil
+}
+
+func (s *memoryAlertStore) List(_ context.Context) ([]*StoredAlert, error) {
+	alerts := make([]*StoredAlert, 0, len(s.alerts))
+	for _, stored := range s.alerts {
+		copied := *stored
+		alerts = append(alerts, &copied)
+	}
+	return alerts, nil
+}
+
+// redisAlertStore keeps alerts in a Redis hash so they survive gateway
+// restarts. Each field expires ttl after its last write, so an alert whose
+// resolve never arrives is eventually dropped; Alertmanager re-sends firing
+// alerts every repeat_interval, which keeps live ones fresh. Per-field
+// expiry needs Redis 7.4 or later.
+type redisAlertStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func (s *redisAlertStore) Get(ctx context.Context, fingerprint string) (*StoredAlert, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, alertStoreTimeout)
+	defer cancel()
+
+	raw, err := s.client.HGet(ctx, alertStoreKey, fingerprint).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("redis HGET failed: %w", err)
+	}
+	var stored StoredAlert
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, false, fmt.Errorf("invalid stored alert %q: %w", fingerprint, err)
+	}
+	return &stored, true, nil
+}
+
+func (s *redisAlertStore) Put(ctx context.Context, alert *StoredAlert) error {
+	raw, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, alertStoreTimeout)
+	defer cancel()
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, alertStoreKey, alert.Fingerprint, raw)
+		pipe.HExpire(ctx, alertStoreKey, s.ttl, alert.Fingerprint)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis HSET failed: %w", err)
+	}
+	return nil
+}
+
+func (s *redisAlertStore) Delete(ctx context.Context, fingerprint string) error {
+	ctx, cancel := context.WithTimeout(ctx, alertStoreTimeout)
+	defer cancel()
+
+	if err := s.client.HDel(ctx, alertStoreKey, fingerprint).Err(); err != nil {
+		return fmt.Errorf("redis HDEL failed: %w", err)
+	}
+	return nil
+}
+
+func (s *redisAlertStore) List(ctx context.Context) ([]*StoredAlert, error) {
+	ctx, cancel := context.WithTimeout(ctx, alertStoreTimeout)
+	defer cancel()
+
+	fields, err := s.client.HGetAll(ctx, alertStoreKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis HGETALL failed: %w", err)
+	}
+	alerts := make([]*StoredAlert, 0, len(fields))
+	for fingerprint, raw := range fields {
+		var stored StoredAlert
+		if err := json.Unmarshal([]byte(raw), &stored); err != nil {
+			slog.Warn("Skipping unreadable stored alert", "fingerprint", fingerprint, "error", err)
+			continue
+		}
+		alerts = append(alerts, &stored)
+	}
+	return alerts, nil
+}
+
+// initAlertStore keeps active alerts in Redis when redisURL is set and in
+// memory otherwise. An unreachable Redis is only warned about: the client
+// reconnects on its own, and alert endpoints answer 503 until it does.
+func initAlertStore(redisURL string, ttl time.Duration) error {
+	if redisURL == "" {
+		alertStore = newMemoryAlertStore()
+		slog.Warn("Alert store in memory; alerts are lost on restart")
+		return nil
+	}
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	// Without this go-redis ignores context deadlines and waits out its
+	// own socket timeouts
+	opts.ContextTimeoutEnabled = true
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertStoreTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		slog.Warn("Redis unreachable for the alert store", "addr", opts.Addr, "error", err)
+	}
+
+	alertStore = &redisAlertStore{client: client, ttl: ttl}
+	slog.Info("Alert store initialized", "backend", "redis", "addr", opts.Addr, "ttl", ttl)
+	return nil
+}
+
+// alertStoreUnavailable answers a request that the alert store failed
+func alertStoreUnavailable(c *fiber.Ctx, err error) error {
+	slog.Error("Alert store unavailable", "path", c.Path(), "error", err)
+	return respond(c, fiber.StatusServiceUnavailable, fiber.Map{
+		"error": "Alert store unavailable",
+	})
+}
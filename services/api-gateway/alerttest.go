@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Test alert lifetime bounds; the alert resolves itself once its TTL is up
+const (
+	defaultTestAlertTTL = time.Minute
+	maxTestAlertTTL     = time.Hour
+)
+
+// fireTestAlert injects a synthetic firing alert, labeled test="true",
+// into the alert store as the Alertmanager webhook would, then resolves it
+// after the TTL. It exercises the alert list, acknowledgement and the alert
+// stream without touching Prometheus rules. Test alerts are left out of the
+// webhook counters so they don't show up as Alertmanager traffic.
+func fireTestAlert(c *fiber.Ctx) error {
+	req := struct {
+		AlertName  string `json:"alertname"`
+		Severity   string `json:"severity"`
+		Node       string `json:"node"`
+		Summary    string `json:"summary"`
+		TTLSeconds int    `json:"ttl_seconds"`
+	}{
+		AlertName:  "PulseTestAlert",
+		Severity:   "warning",
+		TTLSeconds: int(defaultTestAlertTTL.Seconds()),
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	if err := ValidateName(req.AlertName); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Message,
+			"field": "alertname",
+		})
+	}
+	if req.Node != "" {
+		if err := ValidateID(req.Node); err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Message,
+				"field": "node",
+			})
+		}
+	}
+	if err := ValidateNote(req.Summary); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Message,
+			"field": "summary",
+		})
+	}
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	if ttl < time.Second || ttl > maxTestAlertTTL {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": fmt.Sprintf("ttl_seconds must be between 1 and %d", int(maxTestAlertTTL.Seconds())),
+			"field": "ttl_seconds",
+		})
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+	now := time.Now()
+	alert := Alert{
+		Status: "firing",
+		Labels: Labels{
+			"alertname": req.AlertName,
+			"severity":  normalizeSeverity(req.Severity),
+			"test":      "true",
+		},
+		Annotations: Labels{
+			"summary":     req.Summary,
+			"description": fmt.Sprintf("Synthetic alert from POST /api/v1/alerts/test; resolves after %s", ttl),
+		},
+		StartsAt:    now,
+		EndsAt:      now.Add(ttl),
+		Fingerprint: "test-" + hex.EncodeToString(id),
+	}
+	if req.Node != "" {
+		alert.Labels["node"] = req.Node
+	}
+	if alert.Annotations["summary"] == "" {
+		alert.Annotations["summary"] = "Test alert to verify alert routing"
+	}
+
+	if err := storeAlerts(c.UserContext(), []Alert{alert}, time.Now()); err != nil {
+		return alertStoreUnavailable(c, err)
+	}
+	time.AfterFunc(ttl, func() {
+		resolved := alert
+		resolved.Status = "resolved"
+		resolved.EndsAt = time.Now()
+		if err := storeAlerts(context.Background(), []Alert{resolved}, time.Now()); err != nil {
+			// The store's TTL drops it eventually
+			slog.Error("Failed to resolve test alert", "fingerprint", alert.Fingerprint, "error", err)
+		}
+	})
+
+	slog.Info("Test alert fired",
+		"alertname", alert.Labels["alertname"],
+		"fingerprint", alert.Fingerprint,
+		"ttl", ttl.String(),
+	)
+	recordAudit(c, "alert.test", alert.Fingerprint, map[string]string{
+		"alertname": alert.Labels["alertname"],
+		"ttl":       ttl.String(),
+	})
+
+	return respond(c, fiber.StatusCreated, fiber.Map{
+		"message":     "Test alert fired",
+		"fingerprint": alert.Fingerprint,
+		"labels":      alert.Labels,
+		"resolves_at": alert.EndsAt,
+	})
+}
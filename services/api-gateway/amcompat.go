@@ -0,0 +1,89 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// amCompatReceiver is reported as every alert's receiver; the store keeps
+// alerts from all receivers together, so the gateway names itself
+const amCompatReceiver = "pulse-gateway"
+
+// AMAlertStatus is the status object of an Alertmanager v2 alert
+type AMAlertStatus struct {
+	State       string   `json:"state"`
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+	MutedBy     []string `json:"mutedBy"`
+}
+
+// AMReceiver names a receiver an Alertmanager v2 alert was routed to
+type AMReceiver struct {
+	Name string `json:"name"`
+}
+
+// AMAlert is a stored alert in Alertmanager's v2 GettableAlert shape
+type AMAlert struct {
+	Labels       Labels        `json:"labels"`
+	Annotations  Labels        `json:"annotations"`
+	Receivers    []AMReceiver  `json:"receivers"`
+	Fingerprint  string        `json:"fingerprint"`
+	StartsAt     time.Time     `json:"startsAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+	EndsAt       time.Time     `json:"endsAt"`
+	GeneratorURL string        `json:"generatorURL,omitempty"`
+	Status       AMAlertStatus `json:"status"`
+}
+
+// toAMAlert converts a stored alert. The gateway has no silences or
+// inhibitions, so every stored alert is active; acknowledgements have no
+// Alertmanager equivalent and are left out.
+func toAMAlert(stored *StoredAlert) AMAlert {
+	labels, annotations := stored.Labels, stored.Annotations
+	if labels == nil {
+		labels = Labels{}
+	}
+	if annotations == nil {
+		annotations = Labels{}
+	}
+	return AMAlert{
+		Labels:       labels,
+		Annotations:  annotations,
+		Receivers:    []AMReceiver{{Name: amCompatReceiver}},
+		Fingerprint:  stored.Fingerprint,
+		StartsAt:     stored.StartsAt,
+		UpdatedAt:    stored.LastSeen,
+		EndsAt:       stored.EndsAt,
+		GeneratorURL: stored.GeneratorURL,
+		Status: AMAlertStatus{
+			State:       "active",
+			SilencedBy:  []string{},
+			InhibitedBy: []string{},
+			MutedBy:     []string{},
+		},
+	}
+}
+
+// listAlertsAMCompat returns the stored alerts exactly as Alertmanager's
+// GET /api/v2/alerts would: a bare array sorted by fingerprint, never
+// enveloped, so Alertmanager-aware tools can read the gateway's store
+func listAlertsAMCompat(c *fiber.Ctx) error {
+	alertStoreMutex.RLock()
+	stored, err := alertStore.List(c.UserContext())
+	alertStoreMutex.RUnlock()
+	if err != nil {
+		return alertStoreUnavailable(c, err)
+	}
+
+	alerts := make([]AMAlert, 0, len(stored))
+	for _, entry := range stored {
+		alerts = append(alerts, toAMAlert(entry))
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Fingerprint < alerts[j].Fingerprint
+	})
+	return c.Status(fiber.StatusOK).JSON(alerts)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// redactedValue replaces the value of any sensitive JSON field in logs
+const redactedValue = "***"
+
+// HTTP body logging configuration
+var (
+	redactFields     map[string]bool
+	maxLoggedBodyLen int
+)
+
+func initBodyLogging(fields string, maxBody int) {
+	redactFields = make(map[string]bool)
+	for _, field := range strings.Split(fields, ",") {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			redactFields[field] = true
+		}
+	}
+	maxLoggedBodyLen = maxBody
+	slog.Info("HTTP body logging enabled", "redacted_fields", len(redactFields), "max_body", maxBody)
+}
+
+// redactBody returns a log-safe rendering of a body. JSON is walked and
+// sensitive fields (matched case-insensitively at any depth) are replaced;
+// anything that is not JSON is summarized rather than logged, since it
+// cannot be redacted reliably.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Sprintf("<non-JSON body, %d bytes>", len(body))
+	}
+
+	redacted, err := json.Marshal(redactValue(doc))
+	if err != nil {
+		return fmt.Sprintf("<unloggable body, %d bytes>", len(body))
+	}
+
+	out := string(redacted)
+	if maxLoggedBodyLen > 0 && len(out) > maxLoggedBodyLen {
+		out = out[:maxLoggedBodyLen] + "...(truncated)"
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if redactFields[strings.ToLower(key)] {
+				value[key] = redactedValue
+			} else {
+				value[key] = redactValue(child)
+			}
+		}
+	case []interface{}:
+		for i, child := range value {
+			value[i] = redactValue(child)
+		}
+	}
+	return v
+}
+
+// BodyLoggingMiddleware logs redacted request and response bodies
+func BodyLoggingMiddleware(c *fiber.Ctx) error {
+	err := c.Next()
+
+	// Reading a streamed body would buffer it all, so only note that it streamed
+	responseBody := "<streamed>"
+	if !c.Response().IsBodyStream() {
+		responseBody = redactBody(c.Response().Body())
+	}
+
+	slog.Debug("HTTP exchange",
+		"request_id", c.GetRespHeader(fiber.HeaderXRequestID),
+		"method", c.Method(),
+		"path", c.Path(),
+		"status", c.Response().StatusCode(),
+		"request_body", redactBody(c.Body()),
+		"response_body", responseBody,
+	)
+
+	return err
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// clusterCache holds one cached value per cluster. Each cluster's entry has
+// its own lock, so a slow backend only holds up requests for that cluster,
+// and concurrent misses for one cluster wait on a single upstream fetch.
+type clusterCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*clusterCacheEntry[T]
+}
+
+type clusterCacheEntry[T any] struct {
+	mu        sync.Mutex
+	value     *T
+	fetchedAt time.Time
+}
+
+func newClusterCache[T any]() *clusterCache[T] {
+	return &clusterCache[T]{entries: make(map[string]*clusterCacheEntry[T])}
+}
+
+func (cc *clusterCache[T]) entry(cluster string) *clusterCacheEntry[T] {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	e := cc.entries[cluster]
+	if e == nil {
+		e = &clusterCacheEntry[T]{}
+		cc.entries[cluster] = e
+	}
+	return e
+}
+
+// get returns the cluster's value if it is younger than ttl, and otherwise
+// calls fetch, storing the result when fetch reports it as cacheable. A zero
+// ttl disables caching. On a fetch error the stale value, if any, comes back
+// with the error and its age so the caller can choose to serve it.
+func (cc *clusterCache[T]) get(cluster string, ttl time.Duration, fetch func() (value *T, cacheable bool, err error)) (value *T, hit bool, age time.Duration, err error) {
+	e := cc.entry(cluster)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.value != nil {
+		age = time.Since(e.fetchedAt)
+		if age < ttl {
+			return e.value, true, age, nil
+		}
+	}
+
+	fresh, cacheable, err := fetch()
+	if err != nil {
+		return e.value, true, age, err
+	}
+	if cacheable {
+		e.value = fresh
+		e.fetchedAt = time.Now()
+	}
+	return fresh, false, 0, nil
+}
+
+// invalidate drops a cluster's entry. A fetch already in flight completes
+// into the detached entry, so its possibly outdated result is not served.
+func (cc *clusterCache[T]) invalidate(cluster string) {
+	cc.mu.Lock()
+	delete(cc.entries, cluster)
+	cc.mu.Unlock()
+}
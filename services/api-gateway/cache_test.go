@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClusterCache_ConcurrentMissesShareOneFetch(t *testing.T) {
+	cache := newClusterCache[int]()
+	var fetches atomic.Int32
+	fetch := func() (*int, bool, error) {
+		fetches.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		value := 42
+		return &value, true, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, _, _, err := cache.get("primary", time.Minute, fetch); err != nil || *value != 42 {
+				t.Errorf("get = %v, %v", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("%d fetches, want 1", got)
+	}
+}
+
+func TestClusterCache_SlowClusterDoesNotBlockOthers(t *testing.T) {
+	cache := newClusterCache[int]()
+	release := make(chan struct{})
+	defer close(release)
+
+	go cache.get("slow", time.Minute, func() (*int, bool, error) {
+		<-release
+		return nil, false, errors.New("unreachable")
+	})
+	time.Sleep(10 * time.Millisecond) // Let the slow fetch take its lock
+
+	done := make(chan struct{})
+	go func() {
+		cache.get("fast", time.Minute, func() (*int, bool, error) {
+			value := 1
+			return &value, true, nil
+		})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("fetch for one cluster waited on another cluster's fetch")
+	}
+}
+
+func TestClusterCache_StaleValueOnError(t *testing.T) {
+	cache := newClusterCache[int]()
+	value := 7
+	cache.get("primary", 0, func() (*int, bool, error) { return &value, true, nil })
+
+	got, hit, _, err := cache.get("primary", 0, func() (*int, bool, error) {
+		return nil, false, errors.New("backend down")
+	})
+	if err == nil || got == nil || *got != 7 || !hit {
+		t.Errorf("get = %v, hit %v, %v; want the stale 7 with the error", got, hit, err)
+	}
+
+	cache.invalidate("primary")
+	if got, _, _, _ := cache.get("primary", time.Minute, func() (*int, bool, error) {
+		return nil, false, errors.New("backend down")
+	}); got != nil {
+		t.Errorf("get after invalidate = %v, want nil", *got)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClusterBackend holds the upstream services for one cluster
+type ClusterBackend struct {
+	Name          string `json:"name"`
+	PrometheusURL string `json:"prometheusUrl"`
+	SchedulerURL  string `json:"schedulerUrl"`
+	SimulatorURL  string `json:"simulatorUrl"`
+	Primary       bool   `json:"primary"`
+
+	// Scheduler instances that job-specific requests are sharded across
+	SchedulerShards []string `json:"schedulerShards,omitempty"`
+	ring            *hashRing
+}
+
+// Cluster registry; requests without a cluster param target the primary
+var (
+	clusters       map[string]*ClusterBackend
+	primaryCluster *ClusterBackend
+)
+
+// clusterLocalsKey stores the selected cluster in the request context
+const clusterLocalsKey = "cluster"
+
+// initClusters registers the primary cluster plus any extra clusters from
+// spec ("name=prometheus|scheduler|simulator,..."). An extra cluster's
+// scheduler may be a ";"-separated shard list whose first entry also serves
+// non-job requests. Names are trimmed and lowercased, as ?cluster= is
+// matched lowercased.
+func initClusters(primary ClusterBackend, spec string) error {
+	registry := make(map[string]*ClusterBackend)
+	var problems []error
+
+	add := func(backend ClusterBackend) {
+		if backend.Name == "" {
+			problems = append(problems, errors.New("cluster name is required"))
+			return
+		}
+		if _, exists := registry[backend.Name]; exists {
+			problems = append(problems, fmt.Errorf("duplicate cluster %q", backend.Name))
+			return
+		}
+		upstreams := []*string{&backend.PrometheusURL, &backend.SchedulerURL, &backend.SimulatorURL}
+		for i := range backend.SchedulerShards {
+			upstreams = append(upstreams, &backend.SchedulerShards[i])
+		}
+		for _, upstream := range upstreams {
+			*upstream = strings.TrimSuffix(strings.TrimSpace(*upstream), "/")
+			if u, err := url.Parse(*upstream); err != nil || u.Scheme == "" || u.Host == "" {
+				problems = append(problems, fmt.Errorf("cluster %q: invalid upstream URL %q", backend.Name, *upstream))
+				return
+			}
+		}
+		if len(backend.SchedulerShards) > 1 {
+			backend.ring = newHashRing(backend.SchedulerShards)
+		}
+		registry[backend.Name] = &backend
+	}
+
+	primary.Name = strings.ToLower(strings.TrimSpace(primary.Name))
+	primary.Primary = true
+	add(primary)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, upstreams, ok := strings.Cut(entry, "=")
+		parts := strings.Split(upstreams, "|")
+		if !ok || len(parts) != 3 {
+			problems = append(problems, fmt.Errorf("invalid cluster entry %q, want name=prometheus|scheduler|simulator", entry))
+			continue
+		}
+		backend := ClusterBackend{
+			Name:          strings.ToLower(strings.TrimSpace(name)),
+			PrometheusURL: parts[0],
+			SchedulerURL:  parts[1],
+			SimulatorURL:  parts[2],
+		}
+		if shards := strings.Split(parts[1], ";"); len(shards) > 1 {
+			backend.SchedulerURL = shards[0]
+			backend.SchedulerShards = shards
+		}
+		add(backend)
+	}
+
+	if len(problems) > 0 {
+		return errors.Join(problems...)
+	}
+
+	clusters = registry
+	primaryCluster = registry[primary.Name]
+	for _, backend := range registry {
+		slog.Info("Cluster registered",
+			"cluster", backend.Name,
+			"primary", backend.Primary,
+			"prometheus", backend.PrometheusURL,
+			"scheduler", backend.SchedulerURL,
+			"simulator", backend.SimulatorURL,
+			"scheduler_shards", len(backend.SchedulerShards),
+		)
+	}
+	return nil
+}
+
+// selectCluster resolves the ?cluster= param against the registry, 404ing on
+// unknown names, and stores the backend for handlers
+func selectCluster(c *fiber.Ctx) error {
+	backend := primaryCluster
+	if name := strings.ToLower(c.Query("cluster")); name != "" {
+		var ok bool
+		if backend, ok = clusters[name]; !ok {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error":   "Unknown cluster",
+				"cluster": name,
+			})
+		}
+	}
+
+	c.Locals(clusterLocalsKey, backend)
+	return c.Next()
+}
+
+// clusterFor returns the cluster a request targets
+func clusterFor(c *fiber.Ctx) *ClusterBackend {
+	if backend, ok := c.Locals(clusterLocalsKey).(*ClusterBackend); ok {
+		return backend
+	}
+	return primaryCluster
+}
+
+// listClusters returns the registered clusters, primary first
+func listClusters(c *fiber.Ctx) error {
+	list := make([]*ClusterBackend, 0, len(clusters))
+	for _, backend := range clusters {
+		list = append(list, backend)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Primary != list[j].Primary {
+			return list[i].Primary
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"clusters": list,
+		"primary":  primaryCluster.Name,
+		"total":    len(list),
+	})
+}
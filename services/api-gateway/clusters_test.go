@@ -0,0 +1,35 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// ?cluster= is matched lowercased, so every cluster name must be stored
+// lowercased, the primary's included
+func TestSelectCluster_NameCase(t *testing.T) {
+	scheduler, _ := fakeScheduler(t)
+	app := newTestApp(t, scheduler.URL)
+
+	unused := "http://127.0.0.1:1"
+	err := initClusters(ClusterBackend{
+		Name:          " Primary ",
+		PrometheusURL: unused,
+		SchedulerURL:  scheduler.URL,
+		SimulatorURL:  unused,
+	}, "Lab="+unused+"|"+scheduler.URL+"|"+unused)
+	if err != nil {
+		t.Fatalf("initClusters: %v", err)
+	}
+
+	for _, name := range []string{"Primary", "primary", "PRIMARY", "Lab", "lab"} {
+		if status, body := get(t, app, "/api/v1/jobs?cluster="+name); status != http.StatusOK {
+			t.Errorf("?cluster=%s: status %d, body %s", name, status, body)
+		}
+	}
+
+	if status, body := get(t, app, "/api/v1/clusters"); status != http.StatusOK || !strings.Contains(body, `"primary":"primary"`) {
+		t.Errorf("/clusters: status %d, body %s; want primary named \"primary\"", status, body)
+	}
+}
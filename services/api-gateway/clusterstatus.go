@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// clusterStatusTimeout bounds the status queries, which back the dashboard
+// header and should give up quickly rather than hang on a slow Prometheus
+const clusterStatusTimeout = 3 * time.Second
+
+// clusterStatusQueries maps each status field to the PromQL that computes it
+// from the node simulator's metrics
+var clusterStatusQueries = map[string]string{
+	"nodes_total": "count(pulse_node_up)",
+	"nodes_up":    "sum(pulse_node_up)",
+	"gpus_total":  "sum(pulse_cluster_gpus_total)",
+	"gpus_active": "count(dcgm_gpu_utilization > 5)",
+}
+
+// fallbackClusterStatus is served, flagged degraded, when Prometheus can't
+// be queried
+var fallbackClusterStatus = fiber.Map{
+	"status":      "healthy",
+	"nodes_total": 8,
+	"nodes_up":    8,
+	"gpus_total":  32,
+	"gpus_active": 28,
+}
+
+// queryVector runs an instant query and returns its series. Scalars and
+// other result types are an error.
+func queryVector(ctx context.Context, promURL, query string) ([]instantSample, error) {
+	data, err := queryPrometheus(ctx, promURL, "/api/v1/query", url.Values{
+		"query":   {query},
+		"timeout": {formatQueryTimeout(clusterStatusTimeout)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var vector struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &vector); err != nil {
+		return nil, fmt.Errorf("query must return an instant vector: %w", err)
+	}
+	samples := make([]instantSample, 0, len(vector.Result))
+	for _, series := range vector.Result {
+		raw, _ := series.Value[1].(string)
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected sample value %q", raw)
+		}
+		samples = append(samples, instantSample{metric: series.Metric, value: value})
+	}
+	return samples, nil
+}
+
+// queryVectorSum runs an instant query and sums its samples. An empty
+// result, such as count() over no matching series, is 0.
+func queryVectorSum(ctx context.Context, promURL, query string) (float64, error) {
+	samples, err := queryVector(ctx, promURL, query)
+	if err != nil {
+		return 0, err
+	}
+	var total float64
+	for _, sample := range samples {
+		total += sample.value
+	}
+	return total, nil
+}
+
+// clusterHealth describes the cluster from its node counts
+func clusterHealth(nodesUp, nodesTotal int) string {
+	switch {
+	case nodesTotal > 0 && nodesUp == nodesTotal:
+		return "healthy"
+	case nodesUp > 0:
+		return "partial"
+	default:
+		return "down"
+	}
+}
+
+// clusterStatus queries a cluster's node and GPU counts from Prometheus. If
+// any query fails the static fallback is returned with degraded set, so
+// callers can tell the numbers aren't live.
+func clusterStatus(ctx context.Context, promURL string) fiber.Map {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		counts   = make(map[string]int, len(clusterStatusQueries))
+		firstErr error
+	)
+	for field, query := range clusterStatusQueries {
+		wg.Add(1)
+		go func(field, query string) {
+			defer wg.Done()
+			value, err := queryVectorSum(ctx, promURL, query)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("%s: %w", field, err)
+				}
+				return
+			}
+			counts[field] = int(value)
+		}(field, query)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		slog.Warn("Cluster status query failed, serving fallback values", "error", firstErr)
+		status := fiber.Map{"degraded": true}
+		for field, value := range fallbackClusterStatus {
+			status[field] = value
+		}
+		return status
+	}
+
+	return fiber.Map{
+		"status":      clusterHealth(counts["nodes_up"], counts["nodes_total"]),
+		"nodes_total": counts["nodes_total"],
+		"nodes_up":    counts["nodes_up"],
+		"gpus_total":  counts["gpus_total"],
+		"gpus_active": counts["gpus_active"],
+		"degraded":    false,
+	}
+}
+
+// getClusterStatus reports node and GPU counts from Prometheus, falling
+// back to static values flagged degraded
+func getClusterStatus(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), clusterStatusTimeout)
+	defer cancel()
+
+	return respond(c, fiber.StatusOK, clusterStatus(ctx, clusterFor(c).PrometheusURL))
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Which snapshots a compared series was found in
+const (
+	presenceBoth   = "both"
+	presenceBefore = "before_only"
+	presenceAfter  = "after_only"
+)
+
+// CompareRequest is the body of POST /metrics/compare. Times are RFC 3339
+// or Unix seconds, as Prometheus accepts them.
+type CompareRequest struct {
+	Query  string `json:"query"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// SeriesComparison is one series' values at the two times. Values are null
+// where the series is missing or not a finite number; percent_change is
+// also null when the before value is 0.
+type SeriesComparison struct {
+	Metric        map[string]string `json:"metric"`
+	Presence      string            `json:"presence"`
+	Before        *float64          `json:"before"`
+	After         *float64          `json:"after"`
+	Delta         *float64          `json:"delta"`
+	PercentChange *float64          `json:"percent_change"`
+}
+
+// instantSample is a series from an instant query result
+type instantSample struct {
+	metric map[string]string
+	value  float64
+}
+
+// parseEvalTime parses an RFC 3339 timestamp or Unix seconds
+func parseEvalTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return t, nil
+	}
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return time.Time{}, fmt.Errorf("invalid time %q, want RFC 3339 or Unix seconds", raw)
+	}
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)), nil
+}
+
+// seriesKey renders a label set in Prometheus' {name="value"} form with
+// names sorted, so the same series matches across snapshots
+func seriesKey(metric map[string]string) string {
+	names := make([]string, 0, len(metric))
+	for name := range metric {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + strconv.Quote(metric[name])
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// instantSnapshot runs query at one evaluation time and returns its series
+// by key. Scalar results count as a single series without labels.
+func instantSnapshot(ctx context.Context, promURL, query string, at time.Time, timeout time.Duration) (map[string]instantSample, error) {
+	data, err := queryPrometheus(ctx, promURL, "/api/v1/query", url.Values{
+		"query":   {query},
+		"time":    {strconv.FormatFloat(float64(at.UnixNano())/1e9, 'f', -1, 64)},
+		"timeout": {formatQueryTimeout(timeout)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unexpected Prometheus result: %w", err)
+	}
+
+	var samples []instantSample
+	switch result.ResultType {
+	case "vector":
+		var vector []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		}
+		if err := json.Unmarshal(result.Result, &vector); err != nil {
+			return nil, fmt.Errorf("unexpected Prometheus result: %w", err)
+		}
+		for _, series := range vector {
+			raw, _ := series.Value[1].(string)
+			value, _ := strconv.ParseFloat(raw, 64)
+			samples = append(samples, instantSample{metric: series.Metric, value: value})
+		}
+	case "scalar":
+		var scalar [2]interface{}
+		if err := json.Unmarshal(result.Result, &scalar); err != nil {
+			return nil, fmt.Errorf("unexpected Prometheus result: %w", err)
+		}
+		raw, _ := scalar[1].(string)
+		value, _ := strconv.ParseFloat(raw, 64)
+		samples = append(samples, instantSample{metric: map[string]string{}, value: value})
+	default:
+		return nil, fmt.Errorf("query returned a %s, want an instant vector or scalar", result.ResultType)
+	}
+
+	snapshot := make(map[string]instantSample, len(samples))
+	for _, sample := range samples {
+		if sample.metric == nil {
+			sample.metric = map[string]string{}
+		}
+		snapshot[seriesKey(sample.metric)] = sample
+	}
+	return snapshot, nil
+}
+
+// finite returns v, or nil when it can't be represented in JSON
+func finite(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+// compareSnapshots pairs up series by label set, sorted by key
+func compareSnapshots(before, after map[string]instantSample) []SeriesComparison {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	comparisons := make([]SeriesComparison, 0, len(keys))
+	for _, key := range keys {
+		b, inBefore := before[key]
+		a, inAfter := after[key]
+		entry := SeriesComparison{Presence: presenceBoth}
+		switch {
+		case !inAfter:
+			entry.Metric = b.metric
+			entry.Presence = presenceBefore
+			entry.Before = finite(b.value)
+		case !inBefore:
+			entry.Metric = a.metric
+			entry.Presence = presenceAfter
+			entry.After = finite(a.value)
+		default:
+			entry.Metric = a.metric
+			entry.Before = finite(b.value)
+			entry.After = finite(a.value)
+			if entry.Before != nil && entry.After != nil {
+				entry.Delta = finite(a.value - b.value)
+				if b.value != 0 {
+					entry.PercentChange = finite((a.value - b.value) / math.Abs(b.value) * 100)
+				}
+			}
+		}
+		comparisons = append(comparisons, entry)
+	}
+	return comparisons
+}
+
+// compareMetrics runs one instant query at two times and returns each
+// series' values side by side with the change between them. Series found
+// at only one of the times are included and marked by presence.
+func compareMetrics(c *fiber.Ctx) error {
+	var req CompareRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": "Request body must be {query, before, after}",
+		})
+	}
+	if req.Query == "" {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": "Query is required",
+			"field": "query",
+		})
+	}
+	if err := ValidateQueryParam(req.Query); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Message,
+			"field": "query",
+		})
+	}
+	before, err := parseEvalTime(req.Before)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+			"field": "before",
+		})
+	}
+	after, err := parseEvalTime(req.After)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+			"field": "after",
+		})
+	}
+
+	timeout, err := queryTimeout(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx, cancel := context.WithTimeout(c.UserContext(), timeout+queryTimeoutGrace)
+	defer cancel()
+
+	promURL := clusterFor(c).PrometheusURL
+	var snapshots [2]map[string]instantSample
+	var errs [2]error
+	var wg sync.WaitGroup
+	for i, at := range []time.Time{before, after} {
+		wg.Add(1)
+		go func(i int, at time.Time) {
+			defer wg.Done()
+			snapshots[i], errs[i] = instantSnapshot(ctx, promURL, req.Query, at, timeout)
+		}(i, at)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			slog.Error("Metrics compare query failed", "query", req.Query, "error", err)
+			return respond(c, fiber.StatusBadGateway, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	series := compareSnapshots(snapshots[0], snapshots[1])
+	counts := map[string]int{presenceBoth: 0, presenceBefore: 0, presenceAfter: 0}
+	for _, entry := range series {
+		counts[entry.Presence]++
+	}
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"query":  req.Query,
+		"before": before.UTC(),
+		"after":  after.UTC(),
+		"series": series,
+		"counts": counts,
+	})
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Debug endpoint authentication
+var debugToken string
+
+func initDebugAuth(token string) {
+	debugToken = token
+	slog.Info("Debug endpoints initialized", "enabled", debugToken != "")
+}
+
+// requireDebugToken guards debug endpoints with a bearer token. With no token
+// configured the endpoints are disabled and look like unknown routes.
+func requireDebugToken(c *fiber.Ctx) error {
+	if debugToken == "" {
+		return respond(c, fiber.StatusNotFound, fiber.Map{
+			"error": "Not found",
+		})
+	}
+
+	if !bearerTokenMatches(c, debugToken) {
+		slog.Warn("Unauthorized debug request", "ip", c.IP(), "path", c.Path())
+		return respond(c, fiber.StatusUnauthorized, fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	return c.Next()
+}
+
+// bearerTokenMatches reports whether the request carries the expected bearer
+// token, compared in constant time
+func bearerTokenMatches(c *fiber.Ctx, expected string) bool {
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// debugAlertStore dumps the raw alert store, including tracking state and
+// recently resolved alerts, without any of the listAlerts shaping
+func debugAlertStore(c *fiber.Ctx) error {
+	alertStoreMutex.RLock()
+	defer alertStoreMutex.RUnlock()
+
+	alerts, err := alertStore.List(c.UserContext())
+	if err != nil {
+		return alertStoreUnavailable(c, err)
+	}
+	active := make(map[string]*StoredAlert, len(alerts))
+	for _, stored := range alerts {
+		active[stored.Fingerprint] = stored
+	}
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"active":         active,
+		"resolved":       resolvedAlerts,
+		"active_count":   len(active),
+		"resolved_count": len(resolvedAlerts),
+	})
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// gpuBusyUtilization is the utilization above which a GPU counts as allocated
+const gpuBusyUtilization = 50.0
+
+// Job rejection codes
+const (
+	rejectNoSchedulableNodes = "no_schedulable_nodes"
+	rejectExceedsNodeSize    = "exceeds_node_capacity"
+	rejectExceedsClusterSize = "exceeds_cluster_capacity"
+	rejectClusterFull        = "cluster_fully_utilized"
+)
+
+// GPU caps reported in rejections: single-node jobs are bounded by the
+// largest node, multi-node jobs by every on-bus GPU in the cluster
+const (
+	gpuLimitLargestNode  = "largest_node"
+	gpuLimitClusterTotal = "cluster_total"
+)
+
+// JobRejection explains why a job cannot be placed
+type JobRejection struct {
+	Code    string    `json:"code"`
+	Message string    `json:"message"`
+	Details fiber.Map `json:"details,omitempty"`
+}
+
+// usableGPUs returns how many of the node's GPUs are on the bus and how many
+// of those are currently idle enough to take work
+func usableGPUs(node SimulatorNode) (present, free int) {
+	for _, gpu := range node.GPUs {
+		if gpu.Ejected {
+			continue
+		}
+		present++
+		if gpu.Utilization < gpuBusyUtilization {
+			free++
+		}
+	}
+	return present, free
+}
+
+// jobCandidates returns the up, schedulable nodes that could run a job
+// asking for gpus GPUs: any such node for a CPU job, GPU nodes otherwise
+func jobCandidates(gpus int, nodes []SimulatorNode) []SimulatorNode {
+	candidates := make([]SimulatorNode, 0, len(nodes))
+	for _, node := range nodes {
+		if node.IsUp && node.Schedulable && (gpus == 0 || len(node.GPUs) > 0) {
+			candidates = append(candidates, node)
+		}
+	}
+	return candidates
+}
+
+// gpuCapRejection checks a GPU count against the cap that applies: the
+// largest candidate node for a single-node job, or every on-bus GPU across
+// the candidates for a multi-node job
+func gpuCapRejection(gpus int, multiNode bool, candidates []SimulatorNode) *JobRejection {
+	maxGPUs, clusterGPUs := 0, 0
+	for _, node := range candidates {
+		present, _ := usableGPUs(node)
+		maxGPUs = max(maxGPUs, present)
+		clusterGPUs += present
+	}
+	if multiNode && gpus > clusterGPUs {
+		return &JobRejection{
+			Code:    rejectExceedsClusterSize,
+			Message: fmt.Sprintf("Multi-node job requests %d GPUs but the cluster has %d", gpus, clusterGPUs),
+			Details: fiber.Map{"requested_gpus": gpus, "gpu_limit": gpuLimitClusterTotal, "cluster_gpus": clusterGPUs},
+		}
+	}
+	if !multiNode && gpus > maxGPUs {
+		return &JobRejection{
+			Code:    rejectExceedsNodeSize,
+			Message: fmt.Sprintf("Job requests %d GPUs but the largest node has %d; set multi_node to span nodes", gpus, maxGPUs),
+			Details: fiber.Map{"requested_gpus": gpus, "gpu_limit": gpuLimitLargestNode, "max_node_gpus": maxGPUs},
+		}
+	}
+	return nil
+}
+
+// evaluateJobFit checks a job against live node capacity and utilization.
+// It returns the nodes the job could start on now, or why not. Memory is
+// per node, so a multi-node job only spreads its GPUs.
+func evaluateJobFit(job JobRequest, nodes []SimulatorNode) ([]string, *JobRejection) {
+	candidates := jobCandidates(job.GPUs, nodes)
+	if len(candidates) == 0 {
+		return nil, &JobRejection{
+			Code:    rejectNoSchedulableNodes,
+			Message: "No up, schedulable nodes can run this kind of job",
+		}
+	}
+
+	if rejection := gpuCapRejection(job.GPUs, job.MultiNode, candidates); rejection != nil {
+		return nil, rejection
+	}
+	maxMemoryGB := 0.0
+	for _, node := range candidates {
+		maxMemoryGB = max(maxMemoryGB, node.MemoryTotalGB)
+	}
+	if float64(job.MemoryGB) > maxMemoryGB {
+		return nil, &JobRejection{
+			Code:    rejectExceedsNodeSize,
+			Message: fmt.Sprintf("Job requests %d GB memory but the largest node has %.0f GB", job.MemoryGB, maxMemoryGB),
+			Details: fiber.Map{"requested_memory_gb": job.MemoryGB, "max_node_memory_gb": maxMemoryGB},
+		}
+	}
+
+	if job.MultiNode && job.GPUs > 0 {
+		return spreadJob(job, candidates)
+	}
+
+	fitting := make([]string, 0)
+	mostFreeGPUs, mostFreeMemoryGB := 0, 0.0
+	for _, node := range candidates {
+		_, free := usableGPUs(node)
+		freeMemoryGB := node.MemoryTotalGB - node.MemoryUsedGB
+		mostFreeGPUs = max(mostFreeGPUs, free)
+		mostFreeMemoryGB = max(mostFreeMemoryGB, freeMemoryGB)
+		if free >= job.GPUs && freeMemoryGB >= float64(job.MemoryGB) {
+			fitting = append(fitting, node.ID)
+		}
+	}
+	if len(fitting) == 0 {
+		return nil, &JobRejection{
+			Code:    rejectClusterFull,
+			Message: "No node currently has enough free GPUs and memory for this job",
+			Details: fiber.Map{
+				"requested_gpus":      job.GPUs,
+				"requested_memory_gb": job.MemoryGB,
+				"most_free_gpus":      mostFreeGPUs,
+				"most_free_memory_gb": mostFreeMemoryGB,
+			},
+		}
+	}
+	return fitting, nil
+}
+
+// spreadJob places a multi-node job's GPUs on the nodes with the most free
+// GPUs and room for its per-node memory, returning the fewest nodes that
+// together hold them
+func spreadJob(job JobRequest, candidates []SimulatorNode) ([]string, *JobRejection) {
+	type nodeFree struct {
+		id   string
+		gpus int
+	}
+	usable := make([]nodeFree, 0, len(candidates))
+	for _, node := range candidates {
+		_, free := usableGPUs(node)
+		if free > 0 && node.MemoryTotalGB-node.MemoryUsedGB >= float64(job.MemoryGB) {
+			usable = append(usable, nodeFree{id: node.ID, gpus: free})
+		}
+	}
+	sort.Slice(usable, func(i, j int) bool {
+		if usable[i].gpus != usable[j].gpus {
+			return usable[i].gpus > usable[j].gpus
+		}
+		return usable[i].id < usable[j].id
+	})
+
+	nodes, placed := make([]string, 0), 0
+	for _, node := range usable {
+		if placed >= job.GPUs {
+			break
+		}
+		nodes = append(nodes, node.id)
+		placed += node.gpus
+	}
+	if placed < job.GPUs {
+		return nil, &JobRejection{
+			Code:    rejectClusterFull,
+			Message: "Nodes with room for this job's memory don't have enough free GPUs between them",
+			Details: fiber.Map{
+				"requested_gpus":      job.GPUs,
+				"requested_memory_gb": job.MemoryGB,
+				"free_gpus":           placed,
+			},
+		}
+	}
+	return nodes, nil
+}
+
+// dryRunJob validates a job and checks it against live cluster capacity
+// without submitting it, so clients can exercise the rejection path
+func dryRunJob(c *fiber.Ctx) error {
+	var job JobRequest
+	if err := c.BodyParser(&job); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if errs := job.Validate(); len(errs) > 0 {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error":   "Invalid job request",
+			"details": errs,
+		})
+	}
+
+	nodes, err := fetchSimulatorNodes(c)
+	if err != nil {
+		slog.Error("Failed to fetch nodes for job dry run", "error", err)
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error": "Node simulator unavailable",
+		})
+	}
+
+	fitting, rejection := evaluateJobFit(job, nodes)
+	if rejection != nil {
+		return respond(c, fiber.StatusUnprocessableEntity, fiber.Map{
+			"accepted":  false,
+			"dry_run":   true,
+			"rejection": rejection,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"accepted":      true,
+		"dry_run":       true,
+		"multi_node":    job.MultiNode,
+		"fitting_nodes": fitting,
+	})
+}
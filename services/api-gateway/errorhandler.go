@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// errorHandler renders errors that reach Fiber in the gateway's structured
+// error shape instead of Fiber's plain-text default. Routing misses get code
+// NOT_FOUND (or METHOD_NOT_ALLOWED) with the requested path; any other error
+// a handler returns without responding is a 500 with code INTERNAL_ERROR.
+func errorHandler(c *fiber.Ctx, err error) error {
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+
+	var fiberErr *fiber.Error
+	if !errors.As(err, &fiberErr) {
+		slog.Error("Unhandled request error",
+			"method", c.Method(),
+			"path", c.Path(),
+			"request_id", requestID,
+			"error", err,
+		)
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error":      "Internal server error",
+			"code":       "INTERNAL_ERROR",
+			"request_id": requestID,
+		})
+	}
+
+	switch fiberErr.Code {
+	case fiber.StatusNotFound:
+		return respond(c, fiber.StatusNotFound, fiber.Map{
+			"error":      "Route not found",
+			"code":       "NOT_FOUND",
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"request_id": requestID,
+		})
+	case fiber.StatusMethodNotAllowed:
+		return respond(c, fiber.StatusMethodNotAllowed, fiber.Map{
+			"error":      "Method not allowed",
+			"code":       "METHOD_NOT_ALLOWED",
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"request_id": requestID,
+		})
+	}
+
+	// Other Fiber errors keep their status, with a code derived from it
+	// ("Request Entity Too Large" becomes REQUEST_ENTITY_TOO_LARGE)
+	return respond(c, fiberErr.Code, fiber.Map{
+		"error":      fiberErr.Message,
+		"code":       strings.ToUpper(strings.ReplaceAll(utils.StatusMessage(fiberErr.Code), " ", "_")),
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gofiber/fiber/v2"
+)
+
+// supportedEncodings in server preference order
+var supportedEncodings = []string{"br", "gzip"}
+
+// ExportSample is one NDJSON line of a metrics export
+type ExportSample struct {
+	Metric    map[string]string `json:"metric"`
+	Timestamp float64           `json:"timestamp"`
+	Value     string            `json:"value"`
+}
+
+// negotiateEncoding picks a content encoding from an Accept-Encoding header,
+// honoring q-values. Returns "" (identity) when nothing supported is acceptable.
+func negotiateEncoding(header string) string {
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		weights[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range supportedEncodings {
+		q, ok := weights[encoding]
+		if !ok {
+			q, ok = weights["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// flushWriter is a compressing writer that can push buffered output through
+type flushWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+func newEncoder(encoding string, w io.Writer) flushWriter {
+	switch encoding {
+	case "br":
+		return brotli.NewWriter(w)
+	case "gzip":
+		return gzip.NewWriter(w)
+	}
+	return nil
+}
+
+// exportMetrics streams a Prometheus range query as NDJSON, one sample per
+// line, compressed per Accept-Encoding
+func exportMetrics(c *fiber.Ctx) error {
+	query := c.Query("query")
+	start := c.Query("start")
+	end := c.Query("end")
+	if query == "" || start == "" || end == "" {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": "query, start and end are required",
+		})
+	}
+
+	timeout, err := queryTimeout(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx, cancel := context.WithTimeout(c.UserContext(), timeout+queryTimeoutGrace)
+	defer cancel()
+
+	data, err := queryPrometheus(ctx, clusterFor(c).PrometheusURL, "/api/v1/query_range", url.Values{
+		"query":   {query},
+		"start":   {start},
+		"end":     {end},
+		"step":    {c.Query("step", "60")},
+		"timeout": {formatQueryTimeout(timeout)},
+	})
+	if err != nil {
+		slog.Error("Metrics export query failed", "query", query, "error", err)
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var matrix struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][2]interface{}  `json:"values"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &matrix); err != nil {
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error": "Unexpected Prometheus result type",
+		})
+	}
+
+	encoding := negotiateEncoding(c.Get(fiber.HeaderAcceptEncoding))
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Set(fiber.HeaderVary, fiber.HeaderAcceptEncoding)
+	if encoding != "" {
+		c.Set(fiber.HeaderContentEncoding, encoding)
+	}
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var out io.Writer = w
+		encoder := newEncoder(encoding, w)
+		if encoder != nil {
+			out = encoder
+			defer encoder.Close()
+		}
+
+		lines := json.NewEncoder(out)
+		for _, series := range matrix.Result {
+			for _, pair := range series.Values {
+				ts, _ := pair[0].(float64)
+				value, _ := pair[1].(string)
+				if err := lines.Encode(ExportSample{Metric: series.Metric, Timestamp: ts, Value: value}); err != nil {
+					slog.Warn("Metrics export aborted", "error", err)
+					return
+				}
+			}
+
+			// Push each series to the client instead of buffering the export
+			if encoder != nil {
+				encoder.Flush()
+			}
+			if err := w.Flush(); err != nil {
+				slog.Warn("Metrics export aborted", "error", err)
+				return
+			}
+		}
+	})
+
+	return nil
+}
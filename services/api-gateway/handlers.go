@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -13,17 +18,11 @@ import (
 )
 
 // Service proxy configuration
-var jobSchedulerURL string
 var aiAssistantURL string
 var httpClient = &http.Client{
 	Timeout: 120 * time.Second, // Longer timeout for AI requests
 }
 
-func initJobSchedulerProxy(url string) {
-	jobSchedulerURL = strings.TrimSuffix(url, "/")
-	slog.Info("Job scheduler proxy initialized", "url", jobSchedulerURL)
-}
-
 func initAIAssistantProxy(url string) {
 	aiAssistantURL = strings.TrimSuffix(url, "/")
 	slog.Info("AI assistant proxy initialized", "url", aiAssistantURL)
@@ -31,38 +30,10 @@ func initAIAssistantProxy(url string) {
 
 // Cluster handlers
 
-func getClusterStatus(c *fiber.Ctx) error {
-	// TODO: Implement actual cluster status from Prometheus
-	return c.JSON(fiber.Map{
-		"status":      "healthy",
-		"nodes_total": 8,
-		"nodes_up":    8,
-		"gpus_total":  32,
-		"gpus_active": 28,
-	})
-}
-
-func getNodes(c *fiber.Ctx) error {
-	// TODO: Fetch from node-simulator or database
-	return c.JSON(fiber.Map{
-		"nodes": []fiber.Map{
-			{"id": "gpu-node-01", "type": "gpu", "status": "up", "gpus": 8},
-			{"id": "gpu-node-02", "type": "gpu", "status": "up", "gpus": 8},
-			{"id": "gpu-node-03", "type": "gpu", "status": "up", "gpus": 8},
-			{"id": "gpu-node-04", "type": "gpu", "status": "up", "gpus": 8},
-			{"id": "cpu-node-01", "type": "cpu", "status": "up"},
-			{"id": "cpu-node-02", "type": "cpu", "status": "up"},
-			{"id": "cpu-node-03", "type": "cpu", "status": "up"},
-			{"id": "cpu-node-04", "type": "cpu", "status": "up"},
-		},
-		"total": 8,
-	})
-}
-
 func getNodeByID(c *fiber.Ctx) error {
 	nodeID := c.Params("id")
 	// TODO: Fetch actual node data
-	return c.JSON(fiber.Map{
+	return respondStub(c, fiber.Map{
 		"id":              nodeID,
 		"type":            "gpu",
 		"status":          "up",
@@ -77,33 +48,76 @@ func getNodeByID(c *fiber.Ctx) error {
 }
 
 func drainNode(c *fiber.Ctx) error {
-	nodeID := c.Params("id")
-	// TODO: Implement drain logic
-	return c.JSON(fiber.Map{
-		"message": "Node drain initiated",
-		"node_id": nodeID,
-		"status":  "draining",
-	})
+	defer invalidateNodeList(clusterFor(c)) // After the change has been made
+	return proxyToNodeSimulator(c, "POST", fmt.Sprintf("/api/nodes/%s/drain", c.Params("id")))
 }
 
 func resumeNode(c *fiber.Ctx) error {
-	nodeID := c.Params("id")
-	// TODO: Implement resume logic
-	return c.JSON(fiber.Map{
-		"message": "Node resumed",
-		"node_id": nodeID,
-		"status":  "up",
+	defer invalidateNodeList(clusterFor(c)) // After the change has been made
+	return proxyToNodeSimulator(c, "POST", fmt.Sprintf("/api/nodes/%s/resume", c.Params("id")))
+}
+
+func cordonNode(c *fiber.Ctx) error {
+	defer invalidateNodeList(clusterFor(c)) // After the change has been made
+	return proxyToNodeSimulator(c, "POST", fmt.Sprintf("/api/nodes/%s/cordon", c.Params("id")))
+}
+
+func uncordonNode(c *fiber.Ctx) error {
+	defer invalidateNodeList(clusterFor(c)) // After the change has been made
+	return proxyToNodeSimulator(c, "POST", fmt.Sprintf("/api/nodes/%s/uncordon", c.Params("id")))
+}
+
+func getGPU(c *fiber.Ctx) error {
+	node, gpuIndex, lerr := lookupGPU(c)
+	if lerr != nil {
+		return respond(c, lerr.status, lerr.body)
+	}
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"node_id": node.ID,
+		"gpu":     node.GPUs[gpuIndex],
 	})
 }
 
+// resetGPU is operator-only, and successful resets are recorded in the
+// audit trail
+func resetGPU(c *fiber.Ctx) error {
+	node, gpuIndex, lerr := lookupGPU(c)
+	if lerr != nil {
+		return respond(c, lerr.status, lerr.body)
+	}
+	if err := proxyToNodeSimulator(c, "POST", fmt.Sprintf("/api/nodes/%s/gpus/%d/reset", node.ID, gpuIndex)); err != nil {
+		return err
+	}
+	if status := c.Response().StatusCode(); status >= 200 && status < 300 {
+		recordAudit(c, "gpu.reset", node.ID, map[string]string{"gpu": strconv.Itoa(gpuIndex)})
+	}
+	return nil
+}
+
 // Job Scheduler Proxy Handlers
 
+// upstreamQuery is the request's query string minus the gateway's own
+// cluster selector
+func upstreamQuery(c *fiber.Ctx) string {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return string(c.Request().URI().QueryString())
+	}
+	query.Del("cluster")
+	return query.Encode()
+}
+
 func proxyToJobScheduler(c *fiber.Ctx, method, path string) error {
-	url := fmt.Sprintf("%s%s", jobSchedulerURL, path)
+	return proxyToScheduler(c, clusterFor(c).SchedulerURL, method, path)
+}
+
+// proxyToScheduler forwards the request to a specific scheduler instance
+func proxyToScheduler(c *fiber.Ctx, baseURL, method, path string) error {
+	url := fmt.Sprintf("%s%s", baseURL, path)
 
 	// Add query string if present
-	if qs := c.Request().URI().QueryString(); len(qs) > 0 {
-		url = fmt.Sprintf("%s?%s", url, string(qs))
+	if qs := upstreamQuery(c); qs != "" {
+		url = fmt.Sprintf("%s?%s", url, qs)
 	}
 
 	var body io.Reader
@@ -111,10 +125,10 @@ func proxyToJobScheduler(c *fiber.Ctx, method, path string) error {
 		body = strings.NewReader(string(c.Body()))
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(c.UserContext(), method, url, body)
 	if err != nil {
 		slog.Error("Failed to create proxy request", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": "Failed to create proxy request",
 		})
 	}
@@ -125,7 +139,7 @@ func proxyToJobScheduler(c *fiber.Ctx, method, path string) error {
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		slog.Error("Job scheduler proxy error", "error", err, "url", url)
-		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
 			"error": "Job scheduler unavailable",
 		})
 	}
@@ -134,31 +148,35 @@ func proxyToJobScheduler(c *fiber.Ctx, method, path string) error {
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		slog.Error("Failed to read proxy response", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": "Failed to read response",
 		})
 	}
 
+	if resp.StatusCode >= 400 {
+		slog.Warn("Job scheduler returned an error", "status", resp.StatusCode, "method", method, "path", path)
+		if wrapSchedulerErrors {
+			return respond(c, resp.StatusCode, upstreamError("scheduler", resp.StatusCode, respBody))
+		}
+	}
+
 	c.Set("Content-Type", "application/json")
-	return c.Status(resp.StatusCode).Send(respBody)
+	return respondRaw(c, resp.StatusCode, respBody)
 }
 
 func proxyListJobs(c *fiber.Ctx) error {
 	return proxyToJobScheduler(c, "GET", "/jobs")
 }
 
-func proxyCreateJob(c *fiber.Ctx) error {
-	return proxyToJobScheduler(c, "POST", "/jobs")
-}
-
+// proxyGetJob and proxyCancelJob route to the scheduler shard owning the job
 func proxyGetJob(c *fiber.Ctx) error {
 	jobID := c.Params("id")
-	return proxyToJobScheduler(c, "GET", fmt.Sprintf("/jobs/%s", jobID))
+	return proxyToScheduler(c, clusterFor(c).schedulerForJob(jobID), "GET", fmt.Sprintf("/jobs/%s", jobID))
 }
 
 func proxyCancelJob(c *fiber.Ctx) error {
 	jobID := c.Params("id")
-	return proxyToJobScheduler(c, "DELETE", fmt.Sprintf("/jobs/%s", jobID))
+	return proxyToScheduler(c, clusterFor(c).schedulerForJob(jobID), "DELETE", fmt.Sprintf("/jobs/%s", jobID))
 }
 
 func proxyListPartitions(c *fiber.Ctx) error {
@@ -176,27 +194,163 @@ func proxyGenerateDemoJobs(c *fiber.Ctx) error {
 
 // Metrics handlers
 
+// queryMetrics proxies an instant query to Prometheus
 func queryMetrics(c *fiber.Ctx) error {
-	// TODO: Proxy to Prometheus
-	query := c.Query("query")
-	return c.JSON(fiber.Map{
-		"status": "success",
-		"query":  query,
-		"note":   "Prometheus proxy not yet implemented",
-	})
+	if problem := metricsQueryProblem(c.Query("query")); problem != nil {
+		return respond(c, fiber.StatusBadRequest, problem)
+	}
+	return proxyToPrometheus(c, "/api/v1/query")
 }
 
+// queryMetricsRange proxies a range query to Prometheus once start, end and
+// step are present and the range runs forwards
 func queryMetricsRange(c *fiber.Ctx) error {
-	// TODO: Proxy to Prometheus
-	query := c.Query("query")
-	start := c.Query("start")
-	end := c.Query("end")
-	return c.JSON(fiber.Map{
-		"status": "success",
-		"query":  query,
-		"start":  start,
-		"end":    end,
-		"note":   "Prometheus proxy not yet implemented",
+	if problem := metricsQueryProblem(c.Query("query")); problem != nil {
+		return respond(c, fiber.StatusBadRequest, problem)
+	}
+	for _, param := range []string{"start", "end", "step"} {
+		if c.Query(param) == "" {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": fmt.Sprintf("%s is required for a range query", param),
+				"field": param,
+			})
+		}
+	}
+	start, err := parseEvalTime(c.Query("start"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+			"field": "start",
+		})
+	}
+	end, err := parseEvalTime(c.Query("end"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+			"field": "end",
+		})
+	}
+	if start.After(end) {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": "start must not be after end",
+			"field": "start",
+		})
+	}
+	return proxyToPrometheus(c, "/api/v1/query_range")
+}
+
+// metricsQueryProblem checks the query param shared by instant and range
+// queries, returning the 400 body when it is missing or invalid
+func metricsQueryProblem(query string) fiber.Map {
+	if query == "" {
+		return fiber.Map{"error": "Query is required", "field": "query"}
+	}
+	if err := ValidateQueryParam(query); err != nil {
+		return fiber.Map{"error": err.Message, "field": "query"}
+	}
+	return nil
+}
+
+// maxBatchQueries limits the queries in one batch request
+const maxBatchQueries = 50
+
+// BatchQuery is a single query in a batch request
+type BatchQuery struct {
+	ID    string `json:"id"`
+	Query string `json:"query"`
+}
+
+// BatchQueryResult is the outcome of a single query in a batch
+type BatchQueryResult struct {
+	ID     string          `json:"id"`
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// queryMetricsBatch runs several instant queries concurrently against
+// Prometheus under a shared deadline, reporting failures per query
+func queryMetricsBatch(c *fiber.Ctx) error {
+	var queries []BatchQuery
+	if err := json.Unmarshal(c.Body(), &queries); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": "Request body must be an array of {id, query}",
+		})
+	}
+	if len(queries) == 0 || len(queries) > maxBatchQueries {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": fmt.Sprintf("Batch must contain between 1 and %d queries", maxBatchQueries),
+		})
+	}
+
+	seen := make(map[string]bool, len(queries))
+	for _, q := range queries {
+		if q.ID == "" || seen[q.ID] {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": "Each query needs a unique id",
+				"id":    q.ID,
+			})
+		}
+		seen[q.ID] = true
+		if q.Query == "" {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": "Query is required",
+				"id":    q.ID,
+			})
+		}
+		if err := ValidateQueryParam(q.Query); err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Message,
+				"id":    q.ID,
+			})
+		}
+	}
+
+	timeout, err := queryTimeout(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	ctx, cancel := context.WithTimeout(c.UserContext(), timeout+queryTimeoutGrace)
+	defer cancel()
+
+	promURL := clusterFor(c).PrometheusURL
+	evalTime := c.Query("time")
+	results := make([]BatchQueryResult, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int, q BatchQuery) {
+			defer wg.Done()
+			params := url.Values{"query": {q.Query}, "timeout": {formatQueryTimeout(timeout)}}
+			if evalTime != "" {
+				params.Set("time", evalTime)
+			}
+			data, err := queryPrometheus(ctx, promURL, "/api/v1/query", params)
+			if err != nil {
+				results[i] = BatchQueryResult{ID: q.ID, Status: "error", Error: err.Error()}
+				return
+			}
+			results[i] = BatchQueryResult{ID: q.ID, Status: "success", Data: data}
+		}(i, q)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Status != "success" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		slog.Warn("Batch query partially failed", "queries", len(queries), "failed", failed)
+	}
+
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"results": results,
+		"total":   len(results),
+		"failed":  failed,
 	})
 }
 
@@ -230,33 +384,132 @@ type Alert struct {
 	Fingerprint  string    `json:"fingerprint"`
 }
 
-// In-memory alert storage (would be Redis/Postgres in production)
+// StoredAlert is an alert plus the gateway's own tracking state
+type StoredAlert struct {
+	Alert
+	FirstSeen      time.Time  `json:"firstSeen"`
+	LastSeen       time.Time  `json:"lastSeen"`
+	ResolvedAt     *time.Time `json:"resolvedAt,omitempty"`
+	FlapCount      int        `json:"flapCount"`
+	Acknowledged   bool       `json:"acknowledged"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	AcknowledgedBy string     `json:"acknowledgedBy,omitempty"`
+	AckNote        string     `json:"ackNote,omitempty"`
+	AckExpiresAt   *time.Time `json:"ackExpiresAt,omitempty"` // Nil means acknowledged until resolved
+	// ManuallyResolved is set when an operator cleared the alert via the API
+	ManuallyResolved bool `json:"manuallyResolved,omitempty"`
+}
+
+// flapWindow is how long resolved alerts are remembered so that a re-fire
+// counts as a flap instead of a brand new alert
+const flapWindow = time.Hour
+
+// maxAckTTL bounds how long a timed acknowledgement can silence an alert
+const maxAckTTL = 7 * 24 * time.Hour
+
+// isAcknowledged reports whether an acknowledgement is in effect at now
+func (s *StoredAlert) isAcknowledged(now time.Time) bool {
+	return s.Acknowledged && (s.AckExpiresAt == nil || now.Before(*s.AckExpiresAt))
+}
+
+// clearAck drops any acknowledgement so the alert surfaces again
+func (s *StoredAlert) clearAck() {
+	s.Acknowledged = false
+	s.AcknowledgedAt = nil
+	s.AcknowledgedBy = ""
+	s.AckNote = ""
+	s.AckExpiresAt = nil
+}
+
+// Active alerts live in alertStore, Redis-backed when REDIS_URL is set.
+// Recently resolved ones are only needed for flap detection and stay in
+// memory.
 var (
-	alertStore      = make(map[string]Alert)
-	alertStoreMutex = &sync.RWMutex{}
+	alertStore      AlertStore = newMemoryAlertStore()
+	resolvedAlerts             = make(map[string]*StoredAlert)
+	alertStoreMutex            = &sync.RWMutex{}
 )
 
 // alertWebhook receives alerts from Alertmanager
 func alertWebhook(c *fiber.Ctx) error {
+	// Observed on return, after the store lock has been released
+	start := time.Now()
+	defer func() { webhookDuration.Observe(time.Since(start).Seconds()) }()
+
 	var webhook AlertmanagerWebhook
 	if err := c.BodyParser(&webhook); err != nil {
+		webhookParseFailuresTotal.Inc()
 		slog.Error("Failed to parse alert webhook", "error", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": "Invalid webhook payload",
 		})
 	}
 
+	if err := ingestAlerts(c.UserContext(), webhook.Alerts); err != nil {
+		// A 5xx makes Alertmanager retry the notification
+		return alertStoreUnavailable(c, err)
+	}
+
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"status":   "received",
+		"received": len(webhook.Alerts),
+	})
+}
+
+// ingestAlerts applies a webhook's batch of firing and resolved alerts to
+// the store and counts them. Alerts are only counted once the whole batch
+// has been stored.
+func ingestAlerts(ctx context.Context, alerts []Alert) error {
+	if err := storeAlerts(ctx, alerts, time.Now()); err != nil {
+		return err
+	}
+
+	// Count outside the store lock to keep the critical section short
+	for _, alert := range alerts {
+		severity := normalizeSeverity(alert.Labels["severity"])
+		alertsReceivedTotal.WithLabelValues(severity, alert.Status).Inc()
+		webhookAlertsProcessedTotal.WithLabelValues(alert.Status).Inc()
+		if alert.Status == "resolved" {
+			alertsResolvedTotal.WithLabelValues(severity).Inc()
+		}
+	}
+	return nil
+}
+
+// storeAlerts applies a batch to the store under its lock, stopping at the
+// first store error
+func storeAlerts(ctx context.Context, alerts []Alert, now time.Time) error {
 	alertStoreMutex.Lock()
-	for _, alert := range webhook.Alerts {
+	defer alertStoreMutex.Unlock()
+
+	pruneResolvedAlerts(now)
+	if err := expireAcks(ctx, now); err != nil {
+		return err
+	}
+	for _, alert := range alerts {
 		if alert.Status == "resolved" {
-			// Remove resolved alerts from store
-			delete(alertStore, alert.Fingerprint)
+			// Remove resolved alerts from store, remembering them for flap detection
+			stored, ok, err := alertStore.Get(ctx, alert.Fingerprint)
+			if err != nil {
+				return err
+			}
+			if ok {
+				if err := alertStore.Delete(ctx, alert.Fingerprint); err != nil {
+					return err
+				}
+				stored.Alert = alert
+				stored.ResolvedAt = &now
+				resolvedAlerts[alert.Fingerprint] = stored
+				publishAlertEvent(stored, alertStateResolved)
+			}
 			slog.Info("Alert resolved",
 				"alertname", alert.Labels["alertname"],
 				"fingerprint", alert.Fingerprint,
 			)
 		} else {
-			alertStore[alert.Fingerprint] = alert
+			if err := trackFiringAlert(ctx, alert, now); err != nil {
+				return err
+			}
 			slog.Info("Alert received",
 				"alertname", alert.Labels["alertname"],
 				"status", alert.Status,
@@ -265,66 +518,471 @@ func alertWebhook(c *fiber.Ctx) error {
 			)
 		}
 	}
-	alertStoreMutex.Unlock()
+	return nil
+}
 
-	return c.JSON(fiber.Map{
-		"status":   "received",
-		"received": len(webhook.Alerts),
-	})
+// trackFiringAlert stores a firing alert, carrying over tracking state from
+// an existing or recently resolved entry. Caller must hold alertStoreMutex.
+func trackFiringAlert(ctx context.Context, alert Alert, now time.Time) error {
+	stored, ok, err := alertStore.Get(ctx, alert.Fingerprint)
+	if err != nil {
+		return err
+	}
+	if ok {
+		// A repeat notification for an alert already firing is not a change
+		stored.Alert = alert
+		stored.LastSeen = now
+		return alertStore.Put(ctx, stored)
+	}
+
+	if previous, ok := resolvedAlerts[alert.Fingerprint]; ok {
+		// Re-fired shortly after resolving: this is a flap
+		previous.Alert = alert
+		previous.LastSeen = now
+		previous.ResolvedAt = nil
+		previous.FlapCount++
+		previous.clearAck()
+		previous.ManuallyResolved = false
+		if err := alertStore.Put(ctx, previous); err != nil {
+			return err
+		}
+		delete(resolvedAlerts, alert.Fingerprint)
+		publishAlertEvent(previous, alertStateFiring)
+		return nil
+	}
+
+	stored = &StoredAlert{
+		Alert:     alert,
+		FirstSeen: now,
+		LastSeen:  now,
+	}
+	if err := alertStore.Put(ctx, stored); err != nil {
+		return err
+	}
+	publishAlertEvent(stored, alertStateFiring)
+	return nil
 }
 
+// pruneResolvedAlerts forgets resolved alerts older than the flap window.
+// Caller must hold alertStoreMutex.
+func pruneResolvedAlerts(now time.Time) {
+	for fingerprint, stored := range resolvedAlerts {
+		if now.Sub(*stored.ResolvedAt) > flapWindow {
+			delete(resolvedAlerts, fingerprint)
+		}
+	}
+}
+
+// expireAcks clears timed acknowledgements that have run out, so alerts that
+// are still firing re-surface. Caller must hold alertStoreMutex.
+func expireAcks(ctx context.Context, now time.Time) error {
+	alerts, err := alertStore.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, stored := range alerts {
+		if stored.Acknowledged && !stored.isAcknowledged(now) {
+			slog.Info("Alert acknowledgement expired",
+				"alertname", stored.Labels["alertname"],
+				"fingerprint", stored.Fingerprint,
+			)
+			stored.clearAck()
+			if err := alertStore.Put(ctx, stored); err != nil {
+				return err
+			}
+			publishAlertEvent(stored, alertStateUnacknowledged)
+		}
+	}
+	return nil
+}
+
+// Orders listAlerts can return alerts in
+const (
+	alertSortNewest   = "newest"   // Latest startsAt first (default)
+	alertSortPriority = "priority" // Highest priority score first
+)
+
+// alertOrders maps each listAlerts sort value to its comparator
+var alertOrders = map[string]func(a, b fiber.Map) bool{
+	alertSortNewest:   newestAlertFirst,
+	alertSortPriority: highestPriorityFirst,
+}
+
+// newestAlertFirst orders alerts by startsAt, latest first, then by
+// fingerprint so the order is stable
+func newestAlertFirst(a, b fiber.Map) bool {
+	sa, sb := a["startsAt"].(time.Time), b["startsAt"].(time.Time)
+	if !sa.Equal(sb) {
+		return sa.After(sb)
+	}
+	return a["fingerprint"].(string) < b["fingerprint"].(string)
+}
+
+// highestPriorityFirst orders alerts by priority score, most impactful
+// first. Ties go to the longest-firing alert, then by fingerprint.
+func highestPriorityFirst(a, b fiber.Map) bool {
+	pa, pb := a["priority"].(float64), b["priority"].(float64)
+	if pa != pb {
+		return pa > pb
+	}
+	sa, sb := a["startsAt"].(time.Time), b["startsAt"].(time.Time)
+	if !sa.Equal(sb) {
+		return sa.Before(sb)
+	}
+	return a["fingerprint"].(string) < b["fingerprint"].(string)
+}
+
+// alertFilter selects alerts by the listAlerts query params; empty fields
+// match every alert
+type alertFilter struct {
+	severity  string
+	alertname string
+	status    string
+}
+
+// matches reports whether an alert passes the filter. Severities are
+// compared after normalization, so "crit" finds critical alerts.
+func (f alertFilter) matches(alert *StoredAlert) bool {
+	if f.severity != "" && normalizeSeverity(alert.Labels["severity"]) != normalizeSeverity(f.severity) {
+		return false
+	}
+	if f.alertname != "" && alert.Labels["alertname"] != f.alertname {
+		return false
+	}
+	if f.status != "" && alert.Status != f.status {
+		return false
+	}
+	return true
+}
+
+// listAlerts returns the active alerts, optionally filtered by severity,
+// alertname and status and paged with limit and offset. total counts the
+// matches before paging. Alerts come newest first unless sort=priority asks
+// for the highest priority score first. Priority scores are computed against every active
+// alert, so filtering doesn't change them.
 func listAlerts(c *fiber.Ctx) error {
+	filter := alertFilter{
+		severity:  c.Query("severity"),
+		alertname: c.Query("alertname"),
+		status:    c.Query("status"),
+	}
+	limit, offset, verr := ValidatePagination(c.Query("limit"), c.Query("offset"))
+	if verr != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": verr.Message,
+			"field": verr.Field,
+		})
+	}
+	order := c.Query("sort", alertSortNewest)
+	less, ok := alertOrders[order]
+	if !ok {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": fmt.Sprintf("sort must be %q or %q", alertSortNewest, alertSortPriority),
+			"field": "sort",
+		})
+	}
+
 	alertStoreMutex.RLock()
-	defer alertStoreMutex.RUnlock()
+	stored, err := alertStore.List(c.UserContext())
+	alertStoreMutex.RUnlock()
+	if err != nil {
+		return alertStoreUnavailable(c, err)
+	}
 
-	alerts := make([]fiber.Map, 0, len(alertStore))
+	alerts := make([]fiber.Map, 0, len(stored))
 	firingCount := 0
+	now := time.Now()
+	nodes := affectedNodes(stored)
 
-	for _, alert := range alertStore {
-		firingCount++
-		alerts = append(alerts, fiber.Map{
-			"fingerprint": alert.Fingerprint,
-			"status":      alert.Status,
-			"labels":      alert.Labels,
-			"annotations": alert.Annotations,
-			"startsAt":    alert.StartsAt,
-			"endsAt":      alert.EndsAt,
-		})
+	for _, alert := range stored {
+		if !filter.matches(alert) {
+			continue
+		}
+		if alert.Status == "firing" {
+			firingCount++
+		}
+		entry := fiber.Map{
+			"fingerprint":  alert.Fingerprint,
+			"status":       alert.Status,
+			"severity":     normalizeSeverity(alert.Labels["severity"]),
+			"priority":     alertPriority(alert, nodes[alert.Labels["alertname"]], now),
+			"acknowledged": alert.isAcknowledged(now),
+			"labels":       alert.Labels,
+			"annotations":  alert.Annotations,
+			"startsAt":     alert.StartsAt,
+			"endsAt":       alert.EndsAt,
+		}
+		if alert.isAcknowledged(now) {
+			entry["acknowledgedAt"] = alert.AcknowledgedAt
+			if alert.AcknowledgedBy != "" {
+				entry["acknowledgedBy"] = alert.AcknowledgedBy
+			}
+			if alert.AckNote != "" {
+				entry["ackNote"] = alert.AckNote
+			}
+			if alert.AckExpiresAt != nil {
+				entry["ackExpiresAt"] = alert.AckExpiresAt
+				entry["ackRemainingSeconds"] = int(alert.AckExpiresAt.Sub(now).Seconds())
+			}
+		}
+		alerts = append(alerts, entry)
 	}
 
-	return c.JSON(fiber.Map{
-		"alerts": alerts,
-		"total":  len(alertStore),
-		"firing": firingCount,
+	sort.Slice(alerts, func(i, j int) bool {
+		return less(alerts[i], alerts[j])
 	})
+
+	total := len(alerts)
+	page := alerts[min(offset, total):]
+	if limit > 0 && len(page) > limit {
+		page = page[:limit]
+	}
+	response := fiber.Map{
+		"alerts":   page,
+		"total":    total,
+		"firing":   firingCount,
+		"offset":   offset,
+		"limit":    nil,
+		"sort":     order,
+		"priority": priorityScoring(),
+	}
+	if limit > 0 {
+		response["limit"] = limit
+	}
+	return respond(c, fiber.StatusOK, response)
 }
 
+// acknowledgeAlert marks an alert as being handled, by the user named in the
+// body's "by" or the X-User header. An optional body adds a note and a TTL
+// after which the acknowledgement lapses and the alert re-surfaces if it is
+// still firing. Acknowledging an acknowledged alert changes nothing and
+// returns the existing acknowledgement.
 func acknowledgeAlert(c *fiber.Ctx) error {
 	alertID := c.Params("id")
 
-	alertStoreMutex.RLock()
-	alert, exists := alertStore[alertID]
-	alertStoreMutex.RUnlock()
+	var req struct {
+		By         string `json:"by"`
+		Note       string `json:"note"`
+		TTLSeconds int    `json:"ttl_seconds"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+	if req.By == "" {
+		req.By = c.Get("X-User")
+	}
+	if err := ValidateAckUser(req.By); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Message,
+			"field": err.Field,
+		})
+	}
+	if err := ValidateNote(req.Note); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Message,
+			"field": err.Field,
+		})
+	}
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	if req.TTLSeconds < 0 || ttl > maxAckTTL {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": fmt.Sprintf("ttl_seconds must be between 0 and %d", int(maxAckTTL.Seconds())),
+			"field": "ttl_seconds",
+		})
+	}
 
+	now := time.Now()
+	stored, acked, err := acknowledgeStoredAlert(c.UserContext(), alertID, req.By, req.Note, ttl, now)
+	if err != nil {
+		return alertStoreUnavailable(c, err)
+	}
+	if stored == nil {
+		return respond(c, fiber.StatusNotFound, fiber.Map{
+			"error":    "Alert not found",
+			"alert_id": alertID,
+		})
+	}
+
+	message := "Alert already acknowledged"
+	if acked {
+		message = "Alert acknowledged"
+		slog.Info("Alert acknowledged",
+			"alert_id", alertID,
+			"alertname", stored.Labels["alertname"],
+			"by", stored.AcknowledgedBy,
+			"ttl", ttl,
+		)
+	}
+
+	response := fiber.Map{
+		"message":         message,
+		"alert_id":        alertID,
+		"status":          "acknowledged",
+		"acknowledged_at": stored.AcknowledgedAt,
+		"acknowledged_by": stored.AcknowledgedBy,
+		"note":            stored.AckNote,
+	}
+	if stored.AckExpiresAt != nil {
+		response["expires_at"] = stored.AckExpiresAt
+	}
+	return respond(c, fiber.StatusOK, response)
+}
+
+// acknowledgeStoredAlert records an acknowledgement on an active alert. It
+// returns nil when there is no such alert, and acked is false when the alert
+// was already acknowledged and has been left as it was.
+func acknowledgeStoredAlert(ctx context.Context, alertID, user, note string, ttl time.Duration, now time.Time) (stored *StoredAlert, acked bool, err error) {
+	alertStoreMutex.Lock()
+	defer alertStoreMutex.Unlock()
+
+	if err := expireAcks(ctx, now); err != nil {
+		return nil, false, err
+	}
+	stored, exists, err := alertStore.Get(ctx, alertID)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	if stored.isAcknowledged(now) {
+		return stored, false, nil
+	}
+	stored.Acknowledged = true
+	stored.AcknowledgedAt = &now
+	stored.AcknowledgedBy = user
+	stored.AckNote = note
+	stored.AckExpiresAt = nil
+	if ttl > 0 {
+		expires := now.Add(ttl)
+		stored.AckExpiresAt = &expires
+	}
+	if err := alertStore.Put(ctx, stored); err != nil {
+		return nil, false, err
+	}
+	publishAlertEvent(stored, alertStateAcknowledged)
+	return stored, true, nil
+}
+
+// unacknowledgeAlert drops an alert's acknowledgement so it surfaces again.
+// Unacknowledging an alert that isn't acknowledged succeeds unchanged.
+func unacknowledgeAlert(c *fiber.Ctx) error {
+	alertID := c.Params("id")
+
+	stored, cleared, err := unacknowledgeStoredAlert(c.UserContext(), alertID, time.Now())
+	if err != nil {
+		return alertStoreUnavailable(c, err)
+	}
+	if stored == nil {
+		return respond(c, fiber.StatusNotFound, fiber.Map{
+			"error":    "Alert not found",
+			"alert_id": alertID,
+		})
+	}
+
+	message := "Alert was not acknowledged"
+	if cleared {
+		message = "Alert unacknowledged"
+		slog.Info("Alert unacknowledged",
+			"alert_id", alertID,
+			"alertname", stored.Labels["alertname"],
+			"by", c.Get("X-User"),
+		)
+	}
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"message":  message,
+		"alert_id": alertID,
+		"status":   "unacknowledged",
+	})
+}
+
+// unacknowledgeStoredAlert clears an active alert's acknowledgement. It
+// returns nil when there is no such alert, and cleared is false when the
+// alert wasn't acknowledged.
+func unacknowledgeStoredAlert(ctx context.Context, alertID string, now time.Time) (stored *StoredAlert, cleared bool, err error) {
+	alertStoreMutex.Lock()
+	defer alertStoreMutex.Unlock()
+
+	if err := expireAcks(ctx, now); err != nil {
+		return nil, false, err
+	}
+	stored, exists, err := alertStore.Get(ctx, alertID)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	if !stored.Acknowledged {
+		return stored, false, nil
+	}
+	stored.clearAck()
+	if err := alertStore.Put(ctx, stored); err != nil {
+		return nil, false, err
+	}
+	publishAlertEvent(stored, alertStateUnacknowledged)
+	return stored, true, nil
+}
+
+// resolveAlert clears a stuck alert from the active store, e.g. when its
+// resolve webhook was lost. Unlike acknowledge, the alert stops being listed.
+func resolveAlert(c *fiber.Ctx) error {
+	alertID := c.Params("id")
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	stored, exists, err := resolveStoredAlert(c.UserContext(), alertID, time.Now())
+	if err != nil {
+		return alertStoreUnavailable(c, err)
+	}
 	if !exists {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		return respond(c, fiber.StatusNotFound, fiber.Map{
 			"error":    "Alert not found",
 			"alert_id": alertID,
 		})
 	}
 
-	slog.Info("Alert acknowledged",
-		"alert_id", alertID,
-		"alertname", alert.Labels["alertname"],
-	)
+	alertsResolvedTotal.WithLabelValues(normalizeSeverity(stored.Labels["severity"])).Inc()
+	recordAudit(c, "alert.resolve", alertID, map[string]string{
+		"alertname": stored.Labels["alertname"],
+		"reason":    req.Reason,
+	})
 
-	return c.JSON(fiber.Map{
-		"message":  "Alert acknowledged",
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"message":  "Alert resolved",
 		"alert_id": alertID,
-		"status":   "acknowledged",
+		"status":   "resolved",
 	})
 }
 
+// resolveStoredAlert moves an active alert to the recently resolved set as
+// manually resolved; exists is false when there is no such alert
+func resolveStoredAlert(ctx context.Context, alertID string, now time.Time) (*StoredAlert, bool, error) {
+	alertStoreMutex.Lock()
+	defer alertStoreMutex.Unlock()
+
+	pruneResolvedAlerts(now)
+	stored, exists, err := alertStore.Get(ctx, alertID)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	if err := alertStore.Delete(ctx, alertID); err != nil {
+		return nil, false, err
+	}
+	stored.ResolvedAt = &now
+	stored.ManuallyResolved = true
+	resolvedAlerts[stored.Fingerprint] = stored
+	publishAlertEvent(stored, alertStateResolved)
+	return stored, true, nil
+}
+
 // AI Assistant Proxy Handlers
 
 func proxyToAIAssistant(c *fiber.Ctx, method, path string) error {
@@ -340,10 +998,10 @@ func proxyToAIAssistant(c *fiber.Ctx, method, path string) error {
 		body = strings.NewReader(string(c.Body()))
 	}
 
-	req, err := http.NewRequest(method, url, body)
+	req, err := http.NewRequestWithContext(c.UserContext(), method, url, body)
 	if err != nil {
 		slog.Error("Failed to create AI proxy request", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": "Failed to create proxy request",
 		})
 	}
@@ -353,7 +1011,7 @@ func proxyToAIAssistant(c *fiber.Ctx, method, path string) error {
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		slog.Error("AI assistant proxy error", "error", err, "url", url)
-		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
 			"error": "AI assistant unavailable",
 		})
 	}
@@ -362,7 +1020,7 @@ func proxyToAIAssistant(c *fiber.Ctx, method, path string) error {
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		slog.Error("Failed to read AI proxy response", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": "Failed to read response",
 		})
 	}
@@ -374,7 +1032,7 @@ func proxyToAIAssistant(c *fiber.Ctx, method, path string) error {
 		}
 	}
 
-	return c.Status(resp.StatusCode).Send(respBody)
+	return respondRaw(c, resp.StatusCode, respBody)
 }
 
 func proxyAIHealth(c *fiber.Ctx) error {
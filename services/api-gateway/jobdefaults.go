@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultJobPartition matches the scheduler's default for submissions
+// without a partition
+const defaultJobPartition = "gpu"
+
+// jobDefaultBounds lists the resource fields a default can be given for,
+// with the range the scheduler accepts. Fields with a non-zero minimum are
+// also filled when submitted as 0, since the scheduler would reject that.
+var jobDefaultBounds = map[string]struct {
+	min, max float64
+	integer  bool
+}{
+	"cpus":               {min: 1, max: 1024, integer: true},
+	"gpus":               {min: 0, max: 64, integer: true},
+	"memory_gb":          {min: 0.1, max: 4096},
+	"time_limit_minutes": {min: 1, max: MaxWallTimeMin, integer: true},
+}
+
+// Per-partition resource defaults for job submissions, loaded from config
+// and replaced on reload
+var (
+	partitionJobDefaults      = map[string]map[string]float64{}
+	partitionJobDefaultsMutex = &sync.RWMutex{}
+)
+
+// initJobDefaults parses a "partition=field:value,...;..." spec such as
+// "debug=cpus:1,memory_gb:4;gpu=cpus:8,gpus:1" into per-partition resource
+// defaults. Malformed entries, and time limits above the partition's
+// wall-time limit, are skipped with a warning.
+func initJobDefaults(spec string) {
+	defaults := make(map[string]map[string]float64)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		partition, fields, ok := strings.Cut(entry, "=")
+		partition = strings.TrimSpace(partition)
+		if !ok || partition == "" {
+			slog.Warn("Ignoring invalid partition job defaults", "entry", entry)
+			continue
+		}
+		for _, field := range strings.Split(fields, ",") {
+			name, raw, ok := strings.Cut(strings.TrimSpace(field), ":")
+			name = strings.TrimSpace(name)
+			bounds, known := jobDefaultBounds[name]
+			value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if !ok || !known || err != nil || value < bounds.min || value > bounds.max ||
+				(bounds.integer && value != math.Trunc(value)) {
+				slog.Warn("Ignoring invalid job default", "partition", partition, "field", field)
+				continue
+			}
+			if limit, ok := partitionWallTimeLimit(partition); ok && name == "time_limit_minutes" && value > float64(limit) {
+				slog.Warn("Ignoring job default above the partition wall-time limit",
+					"partition", partition, "field", field, "limit", limit)
+				continue
+			}
+			if defaults[partition] == nil {
+				defaults[partition] = make(map[string]float64)
+			}
+			defaults[partition][name] = value
+		}
+	}
+	partitionJobDefaultsMutex.Lock()
+	partitionJobDefaults = defaults
+	partitionJobDefaultsMutex.Unlock()
+	slog.Info("Partition job defaults initialized", "defaults", defaults)
+}
+
+// applyJobDefaults fills the resources a submission leaves unset with its
+// partition's defaults. It returns the body to forward and the defaults
+// applied; bodies it can't interpret are passed through for the scheduler
+// to reject.
+func applyJobDefaults(body []byte) ([]byte, map[string]float64) {
+	var submission map[string]json.RawMessage
+	if err := json.Unmarshal(body, &submission); err != nil {
+		return body, nil
+	}
+	partition := defaultJobPartition
+	if raw, ok := submission["partition"]; ok {
+		if err := json.Unmarshal(raw, &partition); err != nil {
+			return body, nil
+		}
+	}
+
+	partitionJobDefaultsMutex.RLock()
+	defaults := partitionJobDefaults[partition]
+	partitionJobDefaultsMutex.RUnlock()
+	if len(defaults) == 0 {
+		return body, nil
+	}
+
+	resources := map[string]json.RawMessage{}
+	if raw, ok := submission["resources"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &resources); err != nil {
+			return body, nil
+		}
+	}
+
+	applied := make(map[string]float64)
+	for name, value := range defaults {
+		if raw, ok := resources[name]; ok && string(raw) != "null" {
+			var current float64
+			if json.Unmarshal(raw, &current) != nil || current != 0 || jobDefaultBounds[name].min == 0 {
+				continue
+			}
+		}
+		resources[name], _ = json.Marshal(value)
+		applied[name] = value
+	}
+	if len(applied) == 0 {
+		return body, nil
+	}
+
+	submission["resources"], _ = json.Marshal(resources)
+	rewritten, err := json.Marshal(submission)
+	if err != nil {
+		return body, nil
+	}
+	return rewritten, applied
+}
+
+// submittedJob holds the fields of a job submission the gateway checks
+// before forwarding it
+type submittedJob struct {
+	Partition string `json:"partition"`
+	MultiNode bool   `json:"multi_node"` // Gateway-only; the scheduler ignores it
+	Resources struct {
+		GPUs             int `json:"gpus"`
+		TimeLimitMinutes int `json:"time_limit_minutes"`
+	} `json:"resources"`
+}
+
+// parseSubmittedJob reads a submission's checked fields, with the partition
+// defaulted as the scheduler does. Bodies it can't interpret are left for
+// the scheduler to reject.
+func parseSubmittedJob(body []byte) (submittedJob, bool) {
+	var job submittedJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return job, false
+	}
+	if job.Partition == "" {
+		job.Partition = defaultJobPartition
+	}
+	return job, true
+}
+
+// submittedGPUCapRejection checks a submission's GPUs against the largest
+// node, or the cluster total with multi_node. Jobs are passed on when the
+// simulator can't be reached or no node can take jobs right now, since the
+// scheduler queues them either way.
+func submittedGPUCapRejection(c *fiber.Ctx, job submittedJob) *JobRejection {
+	if job.Resources.GPUs <= 0 {
+		return nil
+	}
+	nodes, err := fetchSimulatorNodes(c)
+	if err != nil {
+		slog.Warn("GPU capacity unchecked for job submission", "cluster", clusterFor(c).Name, "error", err)
+		return nil
+	}
+	candidates := jobCandidates(job.Resources.GPUs, nodes)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return gpuCapRejection(job.Resources.GPUs, job.MultiNode, candidates)
+}
+
+// proxyCreateJob submits a job to the scheduler after filling in partition
+// defaults, which are echoed back as applied_defaults on success. A time
+// limit above the partition's wall-time limit is rejected before proxying;
+// one left unset gets the scheduler's default. So is a GPU count above live
+// capacity, as in a dry run. Clients accepting text/event-stream get the
+// job's progress until it starts instead.
+func proxyCreateJob(c *fiber.Ctx) error {
+	body, applied := applyJobDefaults(c.Body())
+	if applied != nil {
+		c.Request().SetBody(body)
+	}
+
+	if job, ok := parseSubmittedJob(body); ok {
+		if verr := ValidatePartitionWallTime(job.Partition, job.Resources.TimeLimitMinutes, "resources.time_limit_minutes"); verr != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error":   "Invalid job request",
+				"details": []ValidationError{*verr},
+			})
+		}
+		if rejection := submittedGPUCapRejection(c, job); rejection != nil {
+			return respond(c, fiber.StatusUnprocessableEntity, fiber.Map{
+				"error":     rejection.Message,
+				"rejection": rejection,
+			})
+		}
+	}
+
+	if err := proxyToJobScheduler(c, "POST", "/jobs"); err != nil {
+		return err
+	}
+	if status := c.Response().StatusCode(); status < 200 || status >= 300 {
+		return nil
+	}
+	if wantsEventStream(c) {
+		return streamJobProgress(c, applied)
+	}
+	if applied == nil {
+		return nil
+	}
+
+	if echoed, ok := withAppliedDefaults(c.Response().Body(), applied, wantsEnvelope(c)); ok {
+		c.Response().SetBody(echoed)
+	}
+	return nil
+}
+
+// withAppliedDefaults adds applied_defaults to a scheduler response, inside
+// the envelope's data when the response is enveloped
+func withAppliedDefaults(body []byte, applied map[string]float64, enveloped bool) ([]byte, bool) {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, false
+	}
+	if enveloped {
+		data, ok := withAppliedDefaults(response["data"], applied, false)
+		if !ok {
+			return nil, false
+		}
+		response["data"] = data
+	} else {
+		response["applied_defaults"], _ = json.Marshal(applied)
+	}
+	echoed, err := json.Marshal(response)
+	return echoed, err == nil
+}
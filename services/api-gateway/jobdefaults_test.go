@@ -0,0 +1,166 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// post sends a JSON POST through the app and returns the status and body
+func post(t *testing.T, app *fiber.App, path, body string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("POST %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("POST %s: reading body: %v", path, err)
+	}
+	return resp.StatusCode, string(respBody)
+}
+
+func TestProxyCreateJob_PartitionWallTime(t *testing.T) {
+	scheduler, requests := fakeScheduler(t)
+	app := newTestApp(t, scheduler.URL)
+	initPartitionLimits("gpu=7200,debug=30")
+	initJobDefaults("cpu=time_limit_minutes:120")
+	t.Cleanup(func() {
+		initPartitionLimits("")
+		initJobDefaults("")
+	})
+
+	tests := []struct {
+		name      string
+		body      string
+		wantLimit string // Empty means the job must reach the scheduler
+	}{
+		{"debug job over its limit", `{"name":"j","partition":"debug","resources":{"time_limit_minutes":43200}}`, "debug partition limit of 30 minutes"},
+		{"debug job within its limit", `{"name":"j","partition":"debug","resources":{"time_limit_minutes":30}}`, ""},
+		{"partition-less job counts as gpu", `{"name":"j","resources":{"time_limit_minutes":7201}}`, "gpu partition limit of 7200 minutes"},
+		{"unset time limit", `{"name":"j","partition":"debug"}`, ""},
+		{"partition without a limit", `{"name":"j","partition":"cpu"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := post(t, app, "/api/v1/jobs", tt.body)
+			forwarded := false
+			select {
+			case <-requests:
+				forwarded = true
+			default:
+			}
+
+			if tt.wantLimit == "" {
+				if status != http.StatusOK || !forwarded {
+					t.Errorf("status %d, forwarded %v, body %s; want the job proxied", status, forwarded, body)
+				}
+				return
+			}
+			if status != http.StatusBadRequest || !strings.Contains(body, tt.wantLimit) {
+				t.Errorf("status %d, body %s; want 400 naming the %s", status, body, tt.wantLimit)
+			}
+			if forwarded {
+				t.Error("rejected job reached the scheduler")
+			}
+		})
+	}
+}
+
+func TestInitJobDefaults_SkipsTimeLimitsAbovePartitionLimit(t *testing.T) {
+	initPartitionLimits("debug=30")
+	initJobDefaults("debug=cpus:1,time_limit_minutes:60;gpu=time_limit_minutes:60")
+	t.Cleanup(func() {
+		initPartitionLimits("")
+		initJobDefaults("")
+	})
+
+	partitionJobDefaultsMutex.RLock()
+	defer partitionJobDefaultsMutex.RUnlock()
+	if _, ok := partitionJobDefaults["debug"]["time_limit_minutes"]; ok {
+		t.Error("debug time_limit_minutes default of 60 kept despite the 30 minute limit")
+	}
+	if got := partitionJobDefaults["debug"]["cpus"]; got != 1 {
+		t.Errorf("debug cpus default = %v, want 1", got)
+	}
+	if got := partitionJobDefaults["gpu"]["time_limit_minutes"]; got != 60 {
+		t.Errorf("gpu time_limit_minutes default = %v, want 60 (no gpu limit configured)", got)
+	}
+}
+
+// fakeSimulator serves /api/nodes with two up, schedulable nodes of 8 GPUs
+func fakeSimulator(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	gpus := strings.TrimSuffix(strings.Repeat(`{"utilization":0},`, 8), ",")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"nodes":[` +
+			`{"id":"gpu-node-01","type":"gpu","is_up":true,"schedulable":true,"memory_total_gb":512,"gpus":[` + gpus + `]},` +
+			`{"id":"gpu-node-02","type":"gpu","is_up":true,"schedulable":true,"memory_total_gb":512,"gpus":[` + gpus + `]}]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProxyCreateJob_GPUCapacity(t *testing.T) {
+	scheduler, requests := fakeScheduler(t)
+	app := newTestApp(t, scheduler.URL)
+	simulator := fakeSimulator(t)
+
+	tests := []struct {
+		name         string
+		simulatorURL string
+		body         string
+		wantLimit    string // Empty means the job must reach the scheduler
+	}{
+		{"fits the largest node", simulator.URL, `{"name":"j","resources":{"gpus":8}}`, ""},
+		{"over the largest node", simulator.URL, `{"name":"j","resources":{"gpus":9}}`, `"gpu_limit":"largest_node","max_node_gpus":8`},
+		{"multi-node within the cluster", simulator.URL, `{"name":"j","multi_node":true,"resources":{"gpus":16}}`, ""},
+		{"multi-node over the cluster", simulator.URL, `{"name":"j","multi_node":true,"resources":{"gpus":17}}`, `"cluster_gpus":16,"gpu_limit":"cluster_total"`},
+		{"CPU job", simulator.URL, `{"name":"j","partition":"cpu"}`, ""},
+		{"simulator unreachable", "http://127.0.0.1:1", `{"name":"j","resources":{"gpus":64}}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := initClusters(ClusterBackend{
+				Name:          "primary",
+				PrometheusURL: "http://127.0.0.1:1",
+				SchedulerURL:  scheduler.URL,
+				SimulatorURL:  tt.simulatorURL,
+			}, "")
+			if err != nil {
+				t.Fatalf("initClusters: %v", err)
+			}
+
+			status, body := post(t, app, "/api/v1/jobs", tt.body)
+			forwarded := false
+			select {
+			case <-requests:
+				forwarded = true
+			default:
+			}
+
+			if tt.wantLimit == "" {
+				if status != http.StatusOK || !forwarded {
+					t.Errorf("status %d, forwarded %v, body %s; want the job proxied", status, forwarded, body)
+				}
+				return
+			}
+			if status != http.StatusUnprocessableEntity || !strings.Contains(body, tt.wantLimit) {
+				t.Errorf("status %d, body %s; want 422 with %s", status, body, tt.wantLimit)
+			}
+			if forwarded {
+				t.Error("rejected job reached the scheduler")
+			}
+		})
+	}
+}
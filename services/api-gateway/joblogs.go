@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxLogTail matches the scheduler's own cap on ?tail
+const maxLogTail = 10000
+
+// logStreamWriteTimeout bounds each write to a following client. The
+// server's WriteTimeout covers a whole response, so it would cut off any
+// follow longer than a few seconds; the stream pushes the deadline out
+// before every chunk instead.
+const logStreamWriteTimeout = 10 * time.Second
+
+// logStreamClient has no overall timeout since a followed log stays open
+// until the job finishes
+var logStreamClient = &http.Client{
+	Transport: func() *http.Transport {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.ResponseHeaderTimeout = 10 * time.Second
+		return transport
+	}(),
+}
+
+// proxyJobLogs returns a job's output from the scheduler shard owning it.
+// With follow=true the scheduler's stream is relayed chunk by chunk until
+// the job finishes; tail=N limits the output to the last N lines.
+func proxyJobLogs(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if err := ValidateID(jobID); err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Message,
+			"field": err.Field,
+		})
+	}
+
+	query := url.Values{}
+	if raw := c.Query("tail"); raw != "" {
+		tail, err := strconv.Atoi(raw)
+		if err != nil || tail < 1 || tail > maxLogTail {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": fmt.Sprintf("tail must be an integer between 1 and %d", maxLogTail),
+				"field": "tail",
+			})
+		}
+		query.Set("tail", strconv.Itoa(tail))
+	}
+	follow := false
+	if raw := c.Query("follow"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": "follow must be true or false",
+				"field": "follow",
+			})
+		}
+		follow = parsed
+	}
+	query.Set("follow", strconv.FormatBool(follow))
+
+	target := fmt.Sprintf("%s/jobs/%s/logs?%s", clusterFor(c).schedulerForJob(jobID), jobID, query.Encode())
+	client := httpClient
+	if follow {
+		client = logStreamClient
+	}
+	req, err := http.NewRequestWithContext(c.UserContext(), http.MethodGet, target, nil)
+	if err != nil {
+		slog.Error("Failed to create proxy request", "error", err)
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": "Failed to create proxy request",
+		})
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		slog.Error("Job scheduler proxy error", "error", err, "url", target)
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error": "Job scheduler unavailable",
+		})
+	}
+
+	if resp.StatusCode >= 400 || !follow {
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			slog.Error("Failed to read proxy response", "error", err)
+			return respond(c, fiber.StatusInternalServerError, fiber.Map{
+				"error": "Failed to read response",
+			})
+		}
+		if resp.StatusCode >= 400 {
+			slog.Warn("Job scheduler returned an error", "status", resp.StatusCode, "method", "GET", "path", "/jobs/"+jobID+"/logs")
+			if wrapSchedulerErrors {
+				return respond(c, resp.StatusCode, upstreamError("scheduler", resp.StatusCode, body))
+			}
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return respondRaw(c, resp.StatusCode, body)
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+		return c.Status(resp.StatusCode).Send(body)
+	}
+
+	conn := c.Context().Conn()
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set("X-Accel-Buffering", "no") // Keep reverse proxies from holding lines back
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer resp.Body.Close()
+
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := resp.Body.Read(buf)
+			if n > 0 {
+				conn.SetWriteDeadline(time.Now().Add(logStreamWriteTimeout))
+				if _, werr := w.Write(buf[:n]); werr != nil {
+					slog.Debug("Job log client went away", "job_id", jobID, "error", werr)
+					return
+				}
+				if werr := w.Flush(); werr != nil {
+					slog.Debug("Job log client went away", "job_id", jobID, "error", werr)
+					return
+				}
+			}
+			if errors.Is(err, io.EOF) {
+				// The scheduler ends the stream once the job finishes
+				return
+			}
+			if err != nil {
+				slog.Warn("Job log stream interrupted", "job_id", jobID, "error", err)
+				return
+			}
+		}
+	})
+
+	return nil
+}
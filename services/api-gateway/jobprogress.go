@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// How often a streamed submission polls the scheduler; its own scheduling
+// cycle runs once a second
+const jobProgressPollInterval = 500 * time.Millisecond
+
+// jobProgressMaxWait bounds how long a submission stream waits for a job to
+// leave the queue before ending with a timeout event
+const jobProgressMaxWait = 10 * time.Minute
+
+// jobProgressKeepalive is how often an SSE comment is sent while the job's
+// state is unchanged, so idle proxies don't close the stream
+const jobProgressKeepalive = 15 * time.Second
+
+// schedulerJob is the part of a scheduler job the progress stream follows
+type schedulerJob struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+}
+
+// wantsEventStream reports whether the client asked for server-sent events
+func wantsEventStream(c *fiber.Ctx) bool {
+	return strings.Contains(c.Get(fiber.HeaderAccept), "text/event-stream")
+}
+
+// isQueuedState reports whether a job in this state is still waiting to start
+func isQueuedState(state string) bool {
+	return state == "PENDING" || state == "PENDING_DEPENDENCY"
+}
+
+// writeEvent writes one SSE event and flushes it to the client
+func writeEvent(w *bufio.Writer, conn net.Conn, event string, data []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(logStreamWriteTimeout))
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// fetchJob reads a job's current record from the scheduler owning it
+func fetchJob(baseURL, jobID string) (json.RawMessage, schedulerJob, error) {
+	var job schedulerJob
+	resp, err := httpClient.Get(fmt.Sprintf("%s/jobs/%s", baseURL, jobID))
+	if err != nil {
+		return nil, job, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, job, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, job, fmt.Errorf("scheduler returned %d", resp.StatusCode)
+	}
+	var response struct {
+		Job json.RawMessage `json:"job"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, job, err
+	}
+	if err := json.Unmarshal(response.Job, &job); err != nil {
+		return nil, job, err
+	}
+	return response.Job, job, nil
+}
+
+// streamJobProgress turns a successful submission response into an SSE
+// stream. The first event carries the job as submitted, named after its
+// lowercased state ("pending"); each state change after that is sent the
+// same way with the job's current record, so the "running" event shows the
+// node it was placed on. The stream ends once the job has started or
+// finished, or with a "timeout" event if it is still queued after
+// jobProgressMaxWait. A submission response it can't read is sent as is.
+func streamJobProgress(c *fiber.Ctx, applied map[string]float64) error {
+	var submitted struct {
+		Job json.RawMessage `json:"job"`
+	}
+	var job schedulerJob
+	if json.Unmarshal(c.Response().Body(), &submitted) != nil ||
+		json.Unmarshal(submitted.Job, &job) != nil || job.ID == "" {
+		return nil
+	}
+	first := []byte(submitted.Job)
+	if applied != nil {
+		if echoed, ok := withAppliedDefaults(first, applied, false); ok {
+			first = echoed
+		}
+	}
+	first = append([]byte(nil), first...) // The response body is reset below
+	// Poll the scheduler that accepted the submission: proxyCreateJob posts
+	// to the cluster's SchedulerURL, not the shard the job ID hashes to
+	schedulerURL := clusterFor(c).SchedulerURL
+
+	conn := c.Context().Conn()
+	c.Response().ResetBody()
+	c.Status(fiber.StatusOK)
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set("X-Accel-Buffering", "no") // Keep reverse proxies from holding events back
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := writeEvent(w, conn, strings.ToLower(job.State), first); err != nil {
+			slog.Debug("Job progress client went away", "job_id", job.ID, "error", err)
+			return
+		}
+
+		state := job.State
+		started := time.Now()
+		lastWrite := started
+		for isQueuedState(state) {
+			if time.Since(started) >= jobProgressMaxWait {
+				data, _ := json.Marshal(fiber.Map{
+					"id":             job.ID,
+					"state":          state,
+					"waited_seconds": int(time.Since(started).Seconds()),
+				})
+				writeEvent(w, conn, "timeout", data)
+				return
+			}
+			time.Sleep(jobProgressPollInterval)
+
+			raw, current, err := fetchJob(schedulerURL, job.ID)
+			if err != nil {
+				slog.Warn("Job progress poll failed", "job_id", job.ID, "error", err)
+				data, _ := json.Marshal(fiber.Map{
+					"id":    job.ID,
+					"error": "Job scheduler unavailable",
+				})
+				writeEvent(w, conn, "error", data)
+				return
+			}
+			if current.State == state {
+				if time.Since(lastWrite) >= jobProgressKeepalive {
+					conn.SetWriteDeadline(time.Now().Add(logStreamWriteTimeout))
+					if _, err := w.WriteString(": keepalive\n\n"); err != nil || w.Flush() != nil {
+						slog.Debug("Job progress client went away", "job_id", job.ID)
+						return
+					}
+					lastWrite = time.Now()
+				}
+				continue
+			}
+			state = current.State
+			if err := writeEvent(w, conn, strings.ToLower(state), raw); err != nil {
+				slog.Debug("Job progress client went away", "job_id", job.ID, "error", err)
+				return
+			}
+			lastWrite = time.Now()
+		}
+	})
+	return nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SchedulerJob is the subset of a scheduler job needed for statistics
+type SchedulerJob struct {
+	ID         string `json:"id"`
+	State      string `json:"state"`
+	Partition  string `json:"partition"`
+	SubmitTime string `json:"submit_time"`
+	StartTime  string `json:"start_time"`
+}
+
+// JobStats summarizes the job queue across a cluster's scheduler instances
+type JobStats struct {
+	ByState             map[string]int `json:"by_state"`
+	Queued              int            `json:"queued"`
+	Running             int            `json:"running"`
+	Completed           int            `json:"completed"`
+	Failed              int            `json:"failed"`
+	Cancelled           int            `json:"cancelled"`
+	Total               int            `json:"total"`
+	AvgQueueWaitSeconds float64        `json:"avg_queue_wait_seconds"`
+	GeneratedAt         time.Time      `json:"generated_at"`
+}
+
+// jobStatsCacheTTL keeps stats cheap under dashboard polling
+const (
+	jobStatsCacheTTL = 10 * time.Second
+	jobStatsLimit    = 1000 // Scheduler's maximum page size
+)
+
+// Job stats cache, keyed by cluster name
+var jobStatsCache = newClusterCache[JobStats]()
+
+// parseSchedulerTime parses the scheduler's ISO timestamps, which are UTC
+// but may lack a zone suffix
+func parseSchedulerTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// fetchSchedulerJobs retrieves the most recent jobs from the scheduler at baseURL
+func fetchSchedulerJobs(ctx context.Context, baseURL string) ([]SchedulerJob, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/jobs?limit=%d", baseURL, jobStatsLimit), nil)
+	if err != nil {
+		return nil, fmt.Errorf("job scheduler request failed: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("job scheduler request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("job scheduler returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Jobs []SchedulerJob `json:"jobs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode job scheduler response: %w", err)
+	}
+	return payload.Jobs, nil
+}
+
+// fetchClusterJobs retrieves the most recent jobs from every scheduler
+// instance of the cluster, failing if any of them can't be read
+func fetchClusterJobs(ctx context.Context, backend *ClusterBackend) ([]SchedulerJob, error) {
+	schedulers := backend.allSchedulers()
+	results := make([][]SchedulerJob, len(schedulers))
+	errs := make([]error, len(schedulers))
+
+	var wg sync.WaitGroup
+	for i, baseURL := range schedulers {
+		wg.Add(1)
+		go func(i int, baseURL string) {
+			defer wg.Done()
+			results[i], errs[i] = fetchSchedulerJobs(ctx, baseURL)
+		}(i, baseURL)
+	}
+	wg.Wait()
+
+	var jobs []SchedulerJob
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", schedulers[i], err)
+		}
+		jobs = append(jobs, results[i]...)
+	}
+	return jobs, nil
+}
+
+func buildJobStats(jobs []SchedulerJob, now time.Time) *JobStats {
+	stats := &JobStats{
+		ByState:     make(map[string]int),
+		Total:       len(jobs),
+		GeneratedAt: now,
+	}
+
+	var totalWait time.Duration
+	waited := 0
+	for _, job := range jobs {
+		stats.ByState[job.State]++
+		switch job.State {
+		case "PENDING", "PENDING_DEPENDENCY", "SUSPENDED":
+			stats.Queued++
+		case "RUNNING", "COMPLETING":
+			stats.Running++
+		case "COMPLETED":
+			stats.Completed++
+		case "FAILED", "TIMEOUT", "NODE_FAIL", "PREEMPTED":
+			stats.Failed++
+		case "CANCELLED":
+			stats.Cancelled++
+		}
+
+		// Wait is submit->start for started jobs, submit->now for queued ones
+		submit, ok := parseSchedulerTime(job.SubmitTime)
+		if !ok {
+			continue
+		}
+		if start, started := parseSchedulerTime(job.StartTime); started {
+			totalWait += start.Sub(submit)
+			waited++
+		} else if job.State == "PENDING" || job.State == "PENDING_DEPENDENCY" {
+			totalWait += now.Sub(submit)
+			waited++
+		}
+	}
+
+	if waited > 0 {
+		stats.AvgQueueWaitSeconds = (totalWait / time.Duration(waited)).Seconds()
+	}
+	return stats
+}
+
+// getJobStats returns job counts by state and the average queue wait
+func getJobStats(c *fiber.Ctx) error {
+	backend := clusterFor(c)
+
+	stats, hit, age, err := jobStatsCache.get(backend.Name, jobStatsCacheTTL, func() (*JobStats, bool, error) {
+		jobs, err := fetchClusterJobs(c.UserContext(), backend)
+		if err != nil {
+			return nil, false, err
+		}
+		return buildJobStats(jobs, time.Now().UTC()), true, nil
+	})
+	if err != nil {
+		slog.Error("Failed to fetch job stats", "cluster", backend.Name, "error", err)
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error": "Job scheduler unavailable",
+		})
+	}
+	return respondCached(c, fiber.StatusOK, stats, hit, age)
+}
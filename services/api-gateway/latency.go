@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Latency injection is a testing aid for client timeout and retry handling.
+// It only exists in development and test environments.
+const (
+	latencyInjectionHeader = "X-Pulse-Inject-Latency-Ms"
+	maxInjectedLatency     = 30 * time.Second
+)
+
+// environments known to PULSE_ENV, mapped to whether latency injection is allowed
+var environments = map[string]bool{
+	"production":  false,
+	"staging":     false,
+	"development": true,
+	"test":        true,
+}
+
+// Latency injection state; injectedLatency is added to every response and
+// the header can override it per request
+var (
+	latencyInjectionEnabled bool
+	injectedLatency         time.Duration
+	injectedLatencyMutex    = &sync.RWMutex{}
+)
+
+// initLatencyInjection validates the environment and enables the latency
+// injection middleware in development/test. Configuring a delay anywhere
+// else is an error rather than being silently ignored.
+func initLatencyInjection(env string, delayMS int) error {
+	allowed, known := environments[env]
+	if !known {
+		return fmt.Errorf("unknown PULSE_ENV %q (want production, staging, development or test)", env)
+	}
+	if !allowed {
+		if delayMS > 0 {
+			return fmt.Errorf("LATENCY_INJECTION_MS is not allowed in %s", env)
+		}
+		return nil
+	}
+
+	latencyInjectionEnabled = true
+	setInjectedLatency(delayMS)
+	slog.Warn("Latency injection active",
+		"env", env,
+		"delay_ms", delayMS,
+		"header", latencyInjectionHeader,
+	)
+	return nil
+}
+
+// setInjectedLatency changes the default delay, capped at maxInjectedLatency
+func setInjectedLatency(delayMS int) {
+	injectedLatencyMutex.Lock()
+	defer injectedLatencyMutex.Unlock()
+	injectedLatency = min(time.Duration(delayMS)*time.Millisecond, maxInjectedLatency)
+}
+
+// LatencyInjectionMiddleware delays responses by the configured latency or
+// the per-request header value
+func LatencyInjectionMiddleware(c *fiber.Ctx) error {
+	injectedLatencyMutex.RLock()
+	delay := injectedLatency
+	injectedLatencyMutex.RUnlock()
+	if raw := c.Get(latencyInjectionHeader); raw != "" {
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms < 0 {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": latencyInjectionHeader + " must be a non-negative integer",
+			})
+		}
+		delay = min(time.Duration(ms)*time.Millisecond, maxInjectedLatency)
+	}
+
+	err := c.Next()
+
+	if delay > 0 {
+		slog.Info("Injecting latency",
+			"request_id", c.GetRespHeader(fiber.HeaderXRequestID),
+			"path", c.Path(),
+			"delay_ms", delay.Milliseconds(),
+		)
+		c.Set("X-Pulse-Injected-Latency-Ms", strconv.FormatInt(delay.Milliseconds(), 10))
+		time.Sleep(delay)
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Alert webhook metrics
+	alertsReceivedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pulse_gateway_alerts_received_total",
+			Help: "Total alerts received from Alertmanager",
+		},
+		[]string{"severity", "status"},
+	)
+
+	alertsResolvedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pulse_gateway_alerts_resolved_total",
+			Help: "Total alerts resolved via Alertmanager",
+		},
+		[]string{"severity"},
+	)
+
+	// Webhook ingestion path; processing is usually well under a millisecond
+	webhookDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "pulse_gateway_webhook_duration_seconds",
+			Help:    "Time to parse and store an Alertmanager webhook",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 4, 8), // 100µs to ~1.6s
+		},
+	)
+
+	webhookAlertsProcessedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pulse_gateway_webhook_alerts_processed_total",
+			Help: "Total alerts processed from Alertmanager webhooks",
+		},
+		[]string{"status"},
+	)
+
+	webhookParseFailuresTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pulse_gateway_webhook_parse_failures_total",
+			Help: "Total Alertmanager webhooks rejected as unparseable",
+		},
+	)
+
+	bodySoftLimitExceededTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pulse_gateway_request_body_soft_limit_exceeded_total",
+			Help: "Requests whose body exceeded BODY_SOFT_LIMIT but stayed under the hard limit",
+		},
+		[]string{"method"},
+	)
+
+	// AI assistant calls holding a slot under AI_MAX_CONCURRENCY
+	aiInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pulse_gateway_ai_in_flight",
+			Help: "AI assistant calls currently in flight through the gateway",
+		},
+	)
+
+	aiRejectedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pulse_gateway_ai_rejected_total",
+			Help: "AI assistant calls rejected with 429 because AI_MAX_CONCURRENCY was reached",
+		},
+	)
+
+	wsClientsConnected = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pulse_gateway_ws_clients",
+			Help: "Live metrics WebSocket clients currently connected",
+		},
+	)
+
+	alertStreamClients = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pulse_gateway_alert_stream_clients",
+			Help: "Alert event stream clients currently connected",
+		},
+	)
+
+	alertEventsDroppedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pulse_gateway_alert_events_dropped_total",
+			Help: "Alert events not delivered because a stream client had fallen behind",
+		},
+	)
+
+	// Request metrics; exemplars carry trace IDs when TRACING_ENABLED
+	httpRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "pulse_gateway_http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route and status",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "route", "status"},
+	)
+)
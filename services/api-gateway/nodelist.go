@@ -0,0 +1,92 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NodeSummary is one node in the gateway's node list
+type NodeSummary struct {
+	ID             string  `json:"id"`
+	Type           string  `json:"type"`
+	Status         string  `json:"status"` // "up" or "down"
+	Schedulable    bool    `json:"schedulable"`
+	GPUs           int     `json:"gpus,omitempty"`
+	CPUUtilization float64 `json:"cpu_utilization"`
+	MemoryUsedGB   float64 `json:"memory_used_gb"`
+	MemoryTotalGB  float64 `json:"memory_total_gb"`
+}
+
+// NodeList is the response of GET /cluster/nodes
+type NodeList struct {
+	Nodes       []NodeSummary `json:"nodes"`
+	Total       int           `json:"total"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// nodeListCacheTTL spares the simulator a request on every dashboard poll;
+// zero disables the cache
+var nodeListCacheTTL = 5 * time.Second
+
+// Node list cache, keyed by cluster name
+var nodeListCache = newClusterCache[NodeList]()
+
+func initNodeListCache(seconds int) {
+	nodeListCacheTTL = time.Duration(seconds) * time.Second
+	slog.Info("Node list cache initialized", "ttl", nodeListCacheTTL.String())
+}
+
+// invalidateNodeList drops a cluster's cached node list after an action
+// that changes node state
+func invalidateNodeList(backend *ClusterBackend) {
+	nodeListCache.invalidate(backend.Name)
+}
+
+func buildNodeList(nodes []SimulatorNode) *NodeList {
+	list := &NodeList{
+		Nodes:       make([]NodeSummary, 0, len(nodes)),
+		Total:       len(nodes),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, node := range nodes {
+		status := "down"
+		if node.IsUp {
+			status = "up"
+		}
+		list.Nodes = append(list.Nodes, NodeSummary{
+			ID:             node.ID,
+			Type:           node.Type,
+			Status:         status,
+			Schedulable:    node.Schedulable,
+			GPUs:           node.GPUCount,
+			CPUUtilization: node.CPUUtilization,
+			MemoryUsedGB:   node.MemoryUsedGB,
+			MemoryTotalGB:  node.MemoryTotalGB,
+		})
+	}
+	return list
+}
+
+// getNodes returns the cluster's nodes from the node-simulator, cached for
+// NODE_LIST_CACHE_SECONDS. Unlike inventory, a failed refresh is a 502
+// rather than a stale list, since node state changes often.
+func getNodes(c *fiber.Ctx) error {
+	backend := clusterFor(c)
+
+	list, hit, age, err := nodeListCache.get(backend.Name, nodeListCacheTTL, func() (*NodeList, bool, error) {
+		nodes, err := fetchSimulatorNodes(c)
+		if err != nil {
+			return nil, false, err
+		}
+		return buildNodeList(nodes), true, nil
+	})
+	if err != nil {
+		slog.Error("Failed to fetch nodes", "cluster", backend.Name, "error", err)
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error": "Node simulator unavailable",
+		})
+	}
+	return respondCached(c, fiber.StatusOK, list, hit, age)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Operator role authentication for state-changing admin actions
+var operatorToken string
+
+func initOperatorAuth(token string) {
+	operatorToken = token
+	slog.Info("Operator endpoints initialized", "enabled", operatorToken != "")
+}
+
+// requireOperator guards operator-only endpoints with a bearer token. With no
+// token configured nobody holds the operator role.
+func requireOperator(c *fiber.Ctx) error {
+	if operatorToken == "" || !bearerTokenMatches(c, operatorToken) {
+		slog.Warn("Operator role required", "ip", c.IP(), "path", c.Path())
+		return respond(c, fiber.StatusForbidden, fiber.Map{
+			"error": "Operator role required",
+		})
+	}
+
+	return c.Next()
+}
+
+// AuditEntry records one operator action
+type AuditEntry struct {
+	Time      time.Time         `json:"time"`
+	Action    string            `json:"action"`
+	Target    string            `json:"target"`
+	RequestID string            `json:"requestId,omitempty"`
+	IP        string            `json:"ip"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// maxAuditEntries bounds the in-memory audit trail
+const maxAuditEntries = 1000
+
+var (
+	auditTrail      []AuditEntry
+	auditTrailMutex = &sync.RWMutex{}
+)
+
+// recordAudit appends an operator action to the audit trail and the log.
+// Request-derived strings are copied since Fiber reuses their buffers.
+func recordAudit(c *fiber.Ctx, action, target string, details map[string]string) {
+	entry := AuditEntry{
+		Time:      time.Now(),
+		Action:    action,
+		Target:    strings.Clone(target),
+		RequestID: strings.Clone(c.GetRespHeader(fiber.HeaderXRequestID)),
+		IP:        c.IP(),
+		Details:   details,
+	}
+
+	auditTrailMutex.Lock()
+	auditTrail = append(auditTrail, entry)
+	if len(auditTrail) > maxAuditEntries {
+		auditTrail = auditTrail[len(auditTrail)-maxAuditEntries:]
+	}
+	auditTrailMutex.Unlock()
+
+	slog.Info("Audit",
+		"action", action,
+		"target", target,
+		"request_id", entry.RequestID,
+		"ip", entry.IP,
+	)
+}
+
+// listAuditTrail returns the audit trail, newest first
+func listAuditTrail(c *fiber.Ctx) error {
+	auditTrailMutex.RLock()
+	entries := make([]AuditEntry, len(auditTrail))
+	for i, entry := range auditTrail {
+		entries[len(auditTrail)-1-i] = entry
+	}
+	auditTrailMutex.RUnlock()
+
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"entries": entries,
+		"total":   len(entries),
+	})
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// startPprofServer serves net/http/pprof on a separate admin port so
+// profiling never shares a listener with the public endpoints
+func startPprofServer(port string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := &http.Server{
+		Addr:        ":" + port,
+		Handler:     mux,
+		ReadTimeout: 10 * time.Second,
+		// No WriteTimeout: CPU profiles and traces stream for their full duration
+	}
+
+	go func() {
+		slog.Info("pprof server starting", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil {
+			slog.Error("pprof server failed", "error", err)
+		}
+	}()
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// queryTimeoutGrace lets Prometheus report its own timeout before the
+// gateway's HTTP deadline cuts the request off
+const queryTimeoutGrace = 2 * time.Second
+
+// Prometheus-side evaluation timeouts, forwarded as the timeout param
+var (
+	defaultQueryTimeout = 10 * time.Second
+	maxQueryTimeout     = 60 * time.Second
+)
+
+// initQueryTimeouts sets the timeout used when a client sends none and the
+// cap applied to client-supplied ones
+func initQueryTimeouts(defaultTimeout, maxTimeout time.Duration) error {
+	if defaultTimeout > maxTimeout {
+		return fmt.Errorf("PROMETHEUS_QUERY_TIMEOUT (%s) exceeds PROMETHEUS_QUERY_MAX_TIMEOUT (%s)", defaultTimeout, maxTimeout)
+	}
+	defaultQueryTimeout = defaultTimeout
+	maxQueryTimeout = maxTimeout
+	slog.Info("Prometheus query timeouts initialized", "default", defaultTimeout, "max", maxTimeout)
+	return nil
+}
+
+// queryTimeout resolves a request's timeout param (a duration such as "30s"
+// or plain seconds), defaulting when absent and capping at the maximum
+func queryTimeout(c *fiber.Ctx) (time.Duration, error) {
+	raw := c.Query("timeout")
+	if raw == "" {
+		return defaultQueryTimeout, nil
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil {
+		seconds, ferr := strconv.ParseFloat(raw, 64)
+		if ferr != nil {
+			return 0, fmt.Errorf("timeout must be a duration such as 30s, got %q", raw)
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %q", raw)
+	}
+	return min(timeout, maxQueryTimeout), nil
+}
+
+// formatQueryTimeout renders a timeout the way the Prometheus API parses it
+func formatQueryTimeout(timeout time.Duration) string {
+	return strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64)
+}
+
+// prometheusResponse is the standard Prometheus HTTP API response envelope
+type prometheusResponse struct {
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	ErrorType string          `json:"errorType"`
+	Error     string          `json:"error"`
+	Warnings  []string        `json:"warnings,omitempty"`
+}
+
+// upstreamSnippetBytes caps how much of an unexpected upstream body is
+// echoed back in an error
+const upstreamSnippetBytes = 200
+
+// bodySnippet returns the start of a response body for an error message,
+// cut on a rune boundary with whitespace collapsed
+func bodySnippet(body []byte) string {
+	if len(body) > upstreamSnippetBytes {
+		body = body[:upstreamSnippetBytes]
+		for len(body) > 0 && !utf8.Valid(body) {
+			body = body[:len(body)-1]
+		}
+	}
+	return strings.Join(strings.Fields(strings.ToValidUTF8(string(body), "")), " ")
+}
+
+// isJSONResponse reports whether an upstream response carries JSON. A body
+// sent without a Content-Type counts if it parses, so only a server that
+// names some other type (an HTML login page, a plain-text 404) is refused.
+func isJSONResponse(resp *http.Response, body []byte) bool {
+	header := resp.Header.Get("Content-Type")
+	if header == "" {
+		return json.Valid(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// queryPrometheus runs an API call such as "/api/v1/query" against the
+// Prometheus at baseURL and returns the data section of a successful response.
+// Queries without a timeout param get the default one.
+func queryPrometheus(ctx context.Context, baseURL, path string, params url.Values) (json.RawMessage, error) {
+	if !params.Has("timeout") {
+		params.Set("timeout", formatQueryTimeout(defaultQueryTimeout))
+	}
+	reqURL := fmt.Sprintf("%s%s?%s", baseURL, path, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Prometheus request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Prometheus response: %w", err)
+	}
+
+	if !isJSONResponse(resp, body) {
+		return nil, fmt.Errorf("prometheus returned %q (status %d), not JSON; check the Prometheus URL: %s",
+			resp.Header.Get("Content-Type"), resp.StatusCode, bodySnippet(body))
+	}
+
+	var result prometheusResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid Prometheus response (status %d): %w", resp.StatusCode, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("prometheus %s: %s", result.ErrorType, result.Error)
+	}
+	return result.Data, nil
+}
+
+// proxyToPrometheus forwards the request's query params to a Prometheus API
+// path and returns its response verbatim with the upstream status. The
+// timeout param is resolved like every other query's, and the gateway's
+// cluster selector is dropped. A response that isn't JSON, as from a URL
+// pointing at some other server, becomes a 502 quoting the start of it.
+func proxyToPrometheus(c *fiber.Ctx, path string) error {
+	timeout, err := queryTimeout(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+			"field": "timeout",
+		})
+	}
+	params, err := url.ParseQuery(upstreamQuery(c))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": "Invalid query string",
+		})
+	}
+	params.Set("timeout", formatQueryTimeout(timeout))
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), timeout+queryTimeoutGrace)
+	defer cancel()
+
+	reqURL := fmt.Sprintf("%s%s?%s", clusterFor(c).PrometheusURL, path, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		slog.Error("Failed to create Prometheus request", "error", err)
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": "Failed to create proxy request",
+		})
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		slog.Error("Prometheus proxy error", "error", err, "path", path)
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error": "Prometheus unavailable",
+		})
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		slog.Error("Failed to read Prometheus response", "error", err)
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": "Failed to read response",
+		})
+	}
+
+	if !isJSONResponse(resp, body) {
+		contentType := resp.Header.Get("Content-Type")
+		slog.Error("Prometheus returned a non-JSON response",
+			"path", path,
+			"status", resp.StatusCode,
+			"content_type", contentType,
+		)
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error":           "Prometheus returned an unexpected content type; check the Prometheus URL",
+			"code":            "UPSTREAM_UNEXPECTED_CONTENT_TYPE",
+			"upstream_status": resp.StatusCode,
+			"content_type":    contentType,
+			"body_snippet":    bodySnippet(body),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return respondRaw(c, resp.StatusCode, body)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newTestApp builds the gateway with schedulerURL as the primary cluster's
+// scheduler and every other upstream pointed at an address nothing serves
+func newTestApp(t *testing.T, schedulerURL string) *fiber.App {
+	t.Helper()
+
+	unused := "http://127.0.0.1:1"
+	err := initClusters(ClusterBackend{
+		Name:          "primary",
+		PrometheusURL: unused,
+		SchedulerURL:  schedulerURL,
+		SimulatorURL:  unused,
+	}, "")
+	if err != nil {
+		t.Fatalf("initClusters: %v", err)
+	}
+	if err := initAlertStore("", time.Hour); err != nil {
+		t.Fatalf("initAlertStore: %v", err)
+	}
+	initUpstreamErrors(false)
+	initRequestTimeout(time.Minute)
+	t.Cleanup(func() { initRequestTimeout(0) })
+
+	return newApp(Config{})
+}
+
+// fakeScheduler serves /jobs, reporting each request's query string, and
+// answers 404 for anything else
+func fakeScheduler(t *testing.T) (*httptest.Server, <-chan string) {
+	t.Helper()
+
+	queries := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/jobs" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"Job not found"}`))
+			return
+		}
+		select {
+		case queries <- r.URL.RawQuery:
+		default:
+		}
+		w.Write([]byte(`{"jobs":[],"total":0}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, queries
+}
+
+// get sends a GET through the app and returns the status and body
+func get(t *testing.T, app *fiber.App, path string) (int, string) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), -1)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("GET %s: reading body: %v", path, err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestProxyToJobScheduler_Passthrough(t *testing.T) {
+	scheduler, _ := fakeScheduler(t)
+	app := newTestApp(t, scheduler.URL)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"success", "/api/v1/jobs", http.StatusOK, `{"jobs":[],"total":0}`},
+		{"scheduler error", "/api/v1/jobs/missing", http.StatusNotFound, `{"detail":"Job not found"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			if status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+			if body != tt.body {
+				t.Errorf("body = %s, want %s", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestProxyToJobScheduler_QueryForwarding(t *testing.T) {
+	scheduler, queries := fakeScheduler(t)
+	app := newTestApp(t, scheduler.URL)
+
+	if status, body := get(t, app, "/api/v1/jobs?cluster=primary&state=RUNNING"); status != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", status, body)
+	}
+	select {
+	case query := <-queries:
+		if query != "state=RUNNING" {
+			t.Errorf("scheduler got query %q, want %q", query, "state=RUNNING")
+		}
+	default:
+		t.Fatal("scheduler was not called")
+	}
+}
+
+func TestProxyToJobScheduler_ConnectionFailure(t *testing.T) {
+	scheduler := httptest.NewServer(http.NotFoundHandler())
+	scheduler.Close() // Nothing listens on its address any more
+	app := newTestApp(t, scheduler.URL)
+
+	status, body := get(t, app, "/api/v1/jobs")
+	if status != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502 (body %s)", status, body)
+	}
+}
+
+func TestProxyToJobScheduler_Timeout(t *testing.T) {
+	scheduler := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done() // Held until the gateway gives up
+	}))
+	t.Cleanup(scheduler.Close)
+	app := newTestApp(t, scheduler.URL)
+	initRequestTimeout(200 * time.Millisecond)
+
+	status, body := get(t, app, "/api/v1/jobs")
+	if status != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want 504 (body %s)", status, body)
+	}
+}
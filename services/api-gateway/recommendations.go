@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// defaultRecommendationRules is used when no rules file is configured
+//
+//go:embed recommendations.json
+var defaultRecommendationRules []byte
+
+// RecommendationRule emits a recommendation for every series its PromQL
+// condition returns. Message placeholders are {{value}} or a label name.
+// Action optionally names a remediation from recommendationActions.
+type RecommendationRule struct {
+	Name     string `json:"name"`
+	Query    string `json:"query"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Action   string `json:"action,omitempty"`
+}
+
+// Recommendation is one matched rule for one series
+type Recommendation struct {
+	Rule     string                `json:"rule"`
+	Severity string                `json:"severity"`
+	Message  string                `json:"message"`
+	Value    float64               `json:"value"`
+	Labels   map[string]string     `json:"labels"`
+	Action   *RecommendationAction `json:"action,omitempty"`
+}
+
+// RecommendationAction is a ready-to-execute gateway request the UI can
+// offer as a one-click remediation
+type RecommendationAction struct {
+	Name   string      `json:"name"`
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Body   interface{} `json:"body,omitempty"`
+}
+
+// actionSpec is a remediation with a safe, well-defined effect. Path takes
+// the value of Label from the matched series; a series without it gets no
+// action.
+type actionSpec struct {
+	Method string
+	Path   string
+	Label  string
+}
+
+// recommendationActions are the only remediations rules may attach
+var recommendationActions = map[string]actionSpec{
+	"drain-node": {Method: fiber.MethodPost, Path: "/api/v1/cluster/nodes/%s/drain", Label: "node"},
+}
+
+var (
+	recommendationRules []RecommendationRule
+	placeholderPattern  = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+)
+
+// initRecommendationRules loads rules from path, or the built-in set when path
+// is empty. Severities must already be initialized.
+func initRecommendationRules(path string) error {
+	data := defaultRecommendationRules
+	source := "built-in"
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return fmt.Errorf("failed to read recommendation rules: %w", err)
+		}
+		source = path
+	}
+
+	rules, err := parseRecommendationRules(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	recommendationRules = rules
+	slog.Info("Recommendation rules initialized", "source", source, "rules", len(rules))
+	return nil
+}
+
+// parseRecommendationRules decodes and validates a JSON rule list, reporting
+// every problem at once
+func parseRecommendationRules(data []byte) ([]RecommendationRule, error) {
+	var rules []RecommendationRule
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+
+	known := make(map[string]bool)
+	for _, level := range severityLevels {
+		known[level.Name] = true
+	}
+
+	var problems []error
+	seen := make(map[string]bool)
+	for i := range rules {
+		rule := &rules[i]
+		label := rule.Name
+		if label == "" {
+			label = fmt.Sprintf("rule %d", i+1)
+			problems = append(problems, fmt.Errorf("%s: name is required", label))
+		} else if seen[rule.Name] {
+			problems = append(problems, fmt.Errorf("%s: duplicate rule name", label))
+		}
+		seen[rule.Name] = true
+
+		if strings.TrimSpace(rule.Query) == "" {
+			problems = append(problems, fmt.Errorf("%s: query is required", label))
+		}
+		rule.Severity = normalizeSeverity(rule.Severity)
+		if !known[rule.Severity] {
+			problems = append(problems, fmt.Errorf("%s: unknown severity %q", label, rule.Severity))
+		}
+		if _, ok := recommendationActions[rule.Action]; rule.Action != "" && !ok {
+			problems = append(problems, fmt.Errorf("%s: unknown action %q", label, rule.Action))
+		}
+		if rule.Message == "" {
+			problems = append(problems, fmt.Errorf("%s: message is required", label))
+		} else if rest := placeholderPattern.ReplaceAllString(rule.Message, ""); strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+			problems = append(problems, fmt.Errorf("%s: malformed placeholder in message", label))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return rules, nil
+}
+
+// renderRecommendation fills a message template from a series' labels and value
+func renderRecommendation(message string, labels map[string]string, value float64) string {
+	return placeholderPattern.ReplaceAllStringFunc(message, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if name == "value" {
+			return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+		}
+		return labels[name]
+	})
+}
+
+// recommendationAction builds the rule's remediation for one series, or nil
+// when the rule has none or the series lacks the label it targets. Actions
+// for a secondary cluster carry its ?cluster= selector.
+func recommendationAction(rule RecommendationRule, labels map[string]string, message string, backend *ClusterBackend) *RecommendationAction {
+	spec, ok := recommendationActions[rule.Action]
+	if !ok || labels[spec.Label] == "" {
+		return nil
+	}
+	path := fmt.Sprintf(spec.Path, url.PathEscape(labels[spec.Label]))
+	if backend != primaryCluster {
+		path += "?cluster=" + url.QueryEscape(backend.Name)
+	}
+	return &RecommendationAction{
+		Name:   rule.Action,
+		Method: spec.Method,
+		Path:   path,
+		Body:   fiber.Map{"reason": message},
+	}
+}
+
+// evaluateRule runs a rule's condition and builds a recommendation per series
+func evaluateRule(ctx context.Context, backend *ClusterBackend, rule RecommendationRule) ([]Recommendation, error) {
+	data, err := queryPrometheus(ctx, backend.PrometheusURL, "/api/v1/query", url.Values{"query": {rule.Query}})
+	if err != nil {
+		return nil, err
+	}
+
+	var vector struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(data, &vector); err != nil {
+		return nil, fmt.Errorf("condition must return an instant vector: %w", err)
+	}
+
+	recommendations := make([]Recommendation, 0, len(vector.Result))
+	for _, sample := range vector.Result {
+		raw, _ := sample.Value[1].(string)
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		message := renderRecommendation(rule.Message, sample.Metric, value)
+		recommendations = append(recommendations, Recommendation{
+			Rule:     rule.Name,
+			Severity: rule.Severity,
+			Message:  message,
+			Value:    value,
+			Labels:   sample.Metric,
+			Action:   recommendationAction(rule, sample.Metric, message, backend),
+		})
+	}
+	return recommendations, nil
+}
+
+// getAIRecommendations evaluates every rule against Prometheus concurrently
+// and returns the matches, most urgent first
+func getAIRecommendations(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), defaultQueryTimeout+queryTimeoutGrace)
+	defer cancel()
+
+	backend := clusterFor(c)
+	var (
+		wg              sync.WaitGroup
+		mu              sync.Mutex
+		recommendations = make([]Recommendation, 0)
+		ruleErrors      = make([]fiber.Map, 0)
+	)
+	for _, rule := range recommendationRules {
+		wg.Add(1)
+		go func(rule RecommendationRule) {
+			defer wg.Done()
+			matched, err := evaluateRule(ctx, backend, rule)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				slog.Warn("Recommendation rule failed", "rule", rule.Name, "error", err)
+				ruleErrors = append(ruleErrors, fiber.Map{"rule": rule.Name, "error": err.Error()})
+				return
+			}
+			recommendations = append(recommendations, matched...)
+		}(rule)
+	}
+	wg.Wait()
+
+	priority := make(map[string]int)
+	for _, level := range severityLevels {
+		priority[level.Name] = level.Priority
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if priority[a.Severity] != priority[b.Severity] {
+			return priority[a.Severity] < priority[b.Severity]
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"recommendations": recommendations,
+		"total":           len(recommendations),
+		"rules":           len(recommendationRules),
+		"errors":          ruleErrors,
+	})
+}
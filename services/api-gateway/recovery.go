@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RecoverMiddleware turns a handler panic into the standard JSON error with
+// code INTERNAL_ERROR, logging the panic and stack under the request ID. It
+// replaces Fiber's recover middleware, whose plain-text 500 clients can't parse.
+func RecoverMiddleware(c *fiber.Ctx) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+		slog.Error("Panic recovered",
+			"panic", r,
+			"method", c.Method(),
+			"path", c.Path(),
+			"request_id", requestID,
+			"stack", string(debug.Stack()),
+		)
+
+		err = respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error":      "Internal server error",
+			"code":       "INTERNAL_ERROR",
+			"request_id": requestID,
+		})
+	}()
+	return c.Next()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pulse/config"
+)
+
+// watchConfigReload re-reads the config file and environment on each SIGHUP
+// and applies the settings tagged reload:"hot". Other changes are logged and
+// ignored until the next restart.
+func watchConfigReload(current Config) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		config.LoadEnvFile()
+
+		var next Config
+		if err := config.Load(&next); err != nil {
+			slog.Error("Config reload failed, keeping current settings", "error", err)
+			continue
+		}
+		if err := reloadConfig(&current, next); err != nil {
+			slog.Error("Config reload failed, keeping current settings", "error", err)
+		}
+	}
+}
+
+// reloadConfig copies the hot-reloadable subset of next into current and
+// pushes each applied change into the running gateway
+func reloadConfig(current *Config, next Config) error {
+	// The latency middleware is only installed where injection is allowed
+	if !latencyInjectionEnabled && next.LatencyInjectionMS > 0 {
+		return fmt.Errorf("LATENCY_INJECTION_MS is not allowed in %s", current.Environment)
+	}
+
+	applied, restart, err := config.Reload(current, &next)
+	if err != nil {
+		return err
+	}
+	if len(restart) > 0 {
+		slog.Warn("Config changes ignored until restart", "settings", restart)
+	}
+
+	for _, key := range applied {
+		switch key {
+		case "LATENCY_INJECTION_MS":
+			setInjectedLatency(current.LatencyInjectionMS)
+		case "PARTITION_MAX_WALL_TIME":
+			initPartitionLimits(current.PartitionMaxWallTime)
+			// Defaults are checked against the new limits
+			initJobDefaults(current.PartitionJobDefaults)
+		case "PARTITION_JOB_DEFAULTS":
+			initJobDefaults(current.PartitionJobDefaults)
+		}
+	}
+	slog.Info("Config reloaded", "applied", applied)
+	return nil
+}
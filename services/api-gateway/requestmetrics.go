@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// tracingEnabled attaches trace-ID exemplars to request metrics
+var tracingEnabled bool
+
+func initTracing(enabled bool) {
+	tracingEnabled = enabled
+	slog.Info("Tracing initialized", "exemplars", tracingEnabled)
+}
+
+// traceIDFor returns the request's trace ID: the W3C traceparent trace-id
+// when a tracing client or proxy propagated one, otherwise the request ID.
+// The result is copied because exemplars outlive Fiber's request buffers.
+func traceIDFor(c *fiber.Ctx) string {
+	// traceparent: version-traceid-parentid-flags
+	parts := strings.Split(c.Get("traceparent"), "-")
+	if len(parts) == 4 && len(parts[1]) == 32 && parts[1] != strings.Repeat("0", 32) {
+		return strings.Clone(parts[1])
+	}
+	return strings.Clone(c.GetRespHeader(fiber.HeaderXRequestID))
+}
+
+// RequestMetricsMiddleware records request latency per route pattern, so
+// the label set stays bounded regardless of IDs in the path
+func RequestMetricsMiddleware(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	// Errors returned to Fiber become responses after middleware runs
+	status := c.Response().StatusCode()
+	if fiberErr, ok := err.(*fiber.Error); ok {
+		status = fiberErr.Code
+	} else if err != nil {
+		status = fiber.StatusInternalServerError
+	}
+
+	observer := httpRequestDuration.WithLabelValues(strings.Clone(c.Method()), c.Route().Path, strconv.Itoa(status))
+	elapsed := time.Since(start).Seconds()
+	if tracingEnabled {
+		if traceID := traceIDFor(c); traceID != "" {
+			observer.(prometheus.ExemplarObserver).ObserveWithExemplar(elapsed, prometheus.Labels{"trace_id": traceID})
+			return err
+		}
+	}
+	observer.Observe(elapsed)
+	return err
+}
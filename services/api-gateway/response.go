@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Response envelope versioning
+const (
+	apiVersion = "v1"
+	// envelopeMediaType is the Accept value that opts a client into the versioned envelope
+	envelopeMediaType = "application/vnd.pulse.v1+json"
+)
+
+// Envelope wraps a response payload with the API version it conforms to.
+// Implemented is false (and Stub true) while a handler still serves demo data.
+type Envelope struct {
+	APIVersion  string      `json:"apiVersion"`
+	Implemented bool        `json:"implemented"`
+	Stub        bool        `json:"stub,omitempty"`
+	Age         *float64    `json:"age,omitempty"`
+	Data        interface{} `json:"data"`
+}
+
+// wantsEnvelope reports whether the client asked for the versioned envelope
+func wantsEnvelope(c *fiber.Ctx) bool {
+	return strings.Contains(c.Get(fiber.HeaderAccept), envelopeMediaType)
+}
+
+// respond writes data as JSON, wrapped in the versioned envelope when the
+// client requested it and bare otherwise
+func respond(c *fiber.Ctx, status int, data interface{}) error {
+	c.Status(status)
+	if wantsEnvelope(c) {
+		return c.JSON(Envelope{APIVersion: apiVersion, Implemented: true, Data: data}, envelopeMediaType)
+	}
+	return c.JSON(data)
+}
+
+// respondCached writes data that may have been served from a cache. The
+// X-Cache header reports HIT or MISS and the envelope carries the age in
+// seconds since the underlying query ran (0 for live data).
+func respondCached(c *fiber.Ctx, status int, data interface{}, hit bool, age time.Duration) error {
+	cacheStatus := "MISS"
+	if hit {
+		cacheStatus = "HIT"
+	} else {
+		age = 0
+	}
+	seconds := age.Seconds()
+	c.Set("X-Cache", cacheStatus)
+	c.Set(fiber.HeaderAge, strconv.Itoa(int(seconds)))
+
+	c.Status(status)
+	if wantsEnvelope(c) {
+		return c.JSON(Envelope{APIVersion: apiVersion, Implemented: true, Age: &seconds, Data: data}, envelopeMediaType)
+	}
+	return c.JSON(data)
+}
+
+// respondStub writes fabricated placeholder data from a handler that is not
+// implemented yet, marked so clients can flag it as demo data rather than
+// presenting it as real
+func respondStub(c *fiber.Ctx, data fiber.Map) error {
+	c.Status(fiber.StatusOK)
+	if wantsEnvelope(c) {
+		return c.JSON(Envelope{APIVersion: apiVersion, Implemented: false, Stub: true, Data: data}, envelopeMediaType)
+	}
+	data["implemented"] = false
+	data["stub"] = true
+	return c.JSON(data)
+}
+
+// respondRaw forwards an upstream response body, wrapping it in the envelope
+// when requested and the body is valid JSON (streams pass through untouched)
+func respondRaw(c *fiber.Ctx, status int, body []byte) error {
+	if wantsEnvelope(c) && json.Valid(body) {
+		return respond(c, status, json.RawMessage(body))
+	}
+	return c.Status(status).Send(body)
+}
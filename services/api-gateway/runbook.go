@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Annotation keys recognized for each runbook field, in order of preference
+var (
+	summaryKeys     = []string{"summary", "title", "message"}
+	descriptionKeys = []string{"description", "details", "detail"}
+	runbookKeys     = []string{"runbook_url", "runbook", "runbookURL", "runbook_link", "playbook"}
+	dashboardKeys   = []string{"dashboard_url", "dashboard", "grafana_url"}
+)
+
+// Runbook is an alert's annotations normalized for an incident panel
+type Runbook struct {
+	AlertID      string            `json:"alert_id"`
+	AlertName    string            `json:"alertname"`
+	Severity     string            `json:"severity"`
+	Summary      string            `json:"summary"`
+	Description  string            `json:"description"`
+	RunbookURL   string            `json:"runbook_url,omitempty"`
+	DashboardURL string            `json:"dashboard_url,omitempty"`
+	Extra        map[string]string `json:"extra,omitempty"`
+	Markdown     string            `json:"markdown"`
+}
+
+// firstAnnotation returns the first non-empty value among keys and marks
+// the matched key as used
+func firstAnnotation(annotations Labels, keys []string, used map[string]bool) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(annotations[key]); value != "" {
+			used[key] = true
+			return value
+		}
+	}
+	return ""
+}
+
+func buildRunbook(id string, alert Alert) Runbook {
+	used := make(map[string]bool)
+	rb := Runbook{
+		AlertID:      id,
+		AlertName:    alert.Labels["alertname"],
+		Severity:     normalizeSeverity(alert.Labels["severity"]),
+		Summary:      firstAnnotation(alert.Annotations, summaryKeys, used),
+		Description:  firstAnnotation(alert.Annotations, descriptionKeys, used),
+		RunbookURL:   firstAnnotation(alert.Annotations, runbookKeys, used),
+		DashboardURL: firstAnnotation(alert.Annotations, dashboardKeys, used),
+	}
+	if rb.Summary == "" {
+		rb.Summary = rb.AlertName
+	}
+
+	for key, value := range alert.Annotations {
+		if !used[key] {
+			if rb.Extra == nil {
+				rb.Extra = make(map[string]string)
+			}
+			rb.Extra[key] = value
+		}
+	}
+
+	rb.Markdown = renderRunbookMarkdown(rb)
+	return rb
+}
+
+func renderRunbookMarkdown(rb Runbook) string {
+	var md strings.Builder
+	fmt.Fprintf(&md, "## %s\n\n", rb.Summary)
+	if rb.AlertName != "" || rb.Severity != "" {
+		fmt.Fprintf(&md, "**Alert:** `%s` | **Severity:** %s\n\n", rb.AlertName, rb.Severity)
+	}
+	if rb.Description != "" {
+		fmt.Fprintf(&md, "%s\n\n", rb.Description)
+	}
+	if rb.RunbookURL != "" {
+		fmt.Fprintf(&md, "- [Runbook](%s)\n", rb.RunbookURL)
+	}
+	if rb.DashboardURL != "" {
+		fmt.Fprintf(&md, "- [Dashboard](%s)\n", rb.DashboardURL)
+	}
+	if len(rb.Extra) > 0 {
+		keys := make([]string, 0, len(rb.Extra))
+		for key := range rb.Extra {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		md.WriteString("\n| Annotation | Value |\n|---|---|\n")
+		for _, key := range keys {
+			fmt.Fprintf(&md, "| %s | %s |\n", key, strings.ReplaceAll(rb.Extra[key], "|", "\\|"))
+		}
+	}
+	return strings.TrimRight(md.String(), "\n") + "\n"
+}
+
+// getAlertRunbook returns an alert's annotations as a structured runbook
+func getAlertRunbook(c *fiber.Ctx) error {
+	alertID := c.Params("id")
+
+	alertStoreMutex.RLock()
+	stored, exists, err := alertStore.Get(c.UserContext(), alertID)
+	alertStoreMutex.RUnlock()
+	if err != nil {
+		return alertStoreUnavailable(c, err)
+	}
+	if !exists {
+		return respond(c, fiber.StatusNotFound, fiber.Map{
+			"error":    "Alert not found",
+			"alert_id": alertID,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, buildRunbook(alertID, stored.Alert))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runSelfTest exercises the app in-process without external dependencies
+// and prints PASS or FAIL. It returns the process exit code.
+func runSelfTest(app *fiber.App) (code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("SELFTEST FAIL: panic: %v\n", r)
+			code = 1
+		}
+	}()
+
+	checks := []struct {
+		name string
+		path string
+	}{
+		{"health", "/health"},
+		{"metrics", "/metrics"},
+		{"alerts", "/api/v1/alerts"},
+		{"alert config", "/api/v1/alerts/config"},
+	}
+
+	for _, check := range checks {
+		resp, err := app.Test(httptest.NewRequest("GET", check.path, nil))
+		if err != nil {
+			fmt.Printf("SELFTEST FAIL: %s: %v\n", check.name, err)
+			return 1
+		}
+		resp.Body.Close()
+		if resp.StatusCode != fiber.StatusOK {
+			fmt.Printf("SELFTEST FAIL: %s: status %d\n", check.name, resp.StatusCode)
+			return 1
+		}
+	}
+
+	if _, err := prometheus.DefaultGatherer.Gather(); err != nil {
+		fmt.Printf("SELFTEST FAIL: metric registration: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("SELFTEST PASS")
+	return 0
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SeverityLevel describes how an alert severity should be presented
+type SeverityLevel struct {
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Priority int    `json:"priority"` // 1 is most urgent
+}
+
+// Severity configuration, ordered from most to least urgent
+var (
+	severityLevels  []SeverityLevel
+	severityAliases map[string]string
+)
+
+// initSeverities parses the severity ("name=color,...", most urgent first)
+// and alias ("alias=name,...") specs. Malformed entries are skipped.
+func initSeverities(levelSpec, aliasSpec string) {
+	levels := make([]SeverityLevel, 0)
+	known := make(map[string]bool)
+	for _, entry := range strings.Split(levelSpec, ",") {
+		name, color, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" || known[name] {
+			if strings.TrimSpace(entry) != "" {
+				slog.Warn("Ignoring invalid severity level", "entry", entry)
+			}
+			continue
+		}
+		known[name] = true
+		levels = append(levels, SeverityLevel{
+			Name:     name,
+			Color:    strings.TrimSpace(color),
+			Priority: len(levels) + 1,
+		})
+	}
+
+	aliases := make(map[string]string)
+	for _, entry := range strings.Split(aliasSpec, ",") {
+		alias, name, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || alias == "" || !known[name] {
+			if strings.TrimSpace(entry) != "" {
+				slog.Warn("Ignoring invalid severity alias", "entry", entry)
+			}
+			continue
+		}
+		aliases[alias] = name
+	}
+
+	severityLevels = levels
+	severityAliases = aliases
+	slog.Info("Alert severities initialized", "levels", len(levels), "aliases", len(aliases))
+}
+
+// normalizeSeverity maps a raw severity label to its canonical name.
+// Unknown severities are lowercased and passed through.
+func normalizeSeverity(raw string) string {
+	severity := strings.ToLower(strings.TrimSpace(raw))
+	if canonical, ok := severityAliases[severity]; ok {
+		return canonical
+	}
+	return severity
+}
+
+// getAlertConfig returns the canonical severity levels and their presentation
+func getAlertConfig(c *fiber.Ctx) error {
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"severities": severityLevels,
+		"aliases":    severityAliases,
+	})
+}
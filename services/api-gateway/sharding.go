@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+// Job sharding strategies for scheduler shards
+const (
+	shardingConsistent = "consistent" // Hash ring: adding a shard moves ~1/N of jobs
+	shardingModulo     = "modulo"     // hash(id) % N: simple, but resizing reshuffles
+)
+
+// ringReplicas is the number of virtual nodes per shard on the hash ring
+const ringReplicas = 100
+
+var jobSharding = shardingConsistent
+
+func initJobSharding(strategy string) error {
+	switch strategy {
+	case shardingConsistent, shardingModulo:
+		jobSharding = strategy
+	default:
+		return fmt.Errorf("unknown JOB_SHARDING %q (want %s or %s)", strategy, shardingConsistent, shardingModulo)
+	}
+	slog.Info("Job sharding initialized", "strategy", jobSharding)
+	return nil
+}
+
+// hashRing maps keys onto shards with consistent hashing
+type hashRing struct {
+	points []uint32
+	owners map[uint32]string
+}
+
+// hashKey spreads keys evenly; FNV skews badly on short sequential job IDs
+func hashKey(key string) uint32 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+func newHashRing(shards []string) *hashRing {
+	ring := &hashRing{owners: make(map[uint32]string)}
+	for _, shard := range shards {
+		for i := 0; i < ringReplicas; i++ {
+			point := hashKey(shard + "#" + strconv.Itoa(i))
+			if _, taken := ring.owners[point]; taken {
+				continue
+			}
+			ring.owners[point] = shard
+			ring.points = append(ring.points, point)
+		}
+	}
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+	return ring
+}
+
+// lookup returns the shard owning key: the first ring point at or after its hash
+func (r *hashRing) lookup(key string) string {
+	h := hashKey(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// schedulerForJob returns the scheduler instance that owns jobID, or the
+// cluster's single scheduler when it isn't sharded
+func (b *ClusterBackend) schedulerForJob(jobID string) string {
+	if len(b.SchedulerShards) < 2 {
+		return b.SchedulerURL
+	}
+	if jobSharding == shardingModulo {
+		return b.SchedulerShards[hashKey(jobID)%uint32(len(b.SchedulerShards))]
+	}
+	return b.ring.lookup(jobID)
+}
+
+// allSchedulers returns every scheduler instance of the cluster: the one
+// serving non-job requests, then any shards not already listed
+func (b *ClusterBackend) allSchedulers() []string {
+	schedulers := []string{b.SchedulerURL}
+	for _, shard := range b.SchedulerShards {
+		if shard != b.SchedulerURL {
+			schedulers = append(schedulers, shard)
+		}
+	}
+	return schedulers
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SimulatorGPU is a GPU as reported by the node-simulator
+type SimulatorGPU struct {
+	Index          int     `json:"index"`
+	Model          string  `json:"model"`
+	MemoryTotalMiB float64 `json:"memory_total_mib"`
+	MaxPowerW      float64 `json:"max_power_w"`
+	Utilization    float64 `json:"utilization"`
+	Ejected        bool    `json:"ejected"`
+}
+
+// SimulatorNode is a node as reported by the node-simulator /api/nodes
+type SimulatorNode struct {
+	ID             string         `json:"id"`
+	Type           string         `json:"type"`
+	IsUp           bool           `json:"is_up"`
+	Schedulable    bool           `json:"schedulable"`
+	CPUUtilization float64        `json:"cpu_utilization"`
+	MemoryUsedGB   float64        `json:"memory_used_gb"`
+	MemoryTotalGB  float64        `json:"memory_total_gb"`
+	GPUCount       int            `json:"gpu_count"`
+	GPUs           []SimulatorGPU `json:"gpus"`
+}
+
+// newSimulatorRequest builds a request to the node-simulator that carries
+// this request's ID, so the simulator's log lines can be matched to ours
+func newSimulatorRequest(c *fiber.Ctx, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(c.UserContext(), method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if id := c.GetRespHeader(fiber.HeaderXRequestID); id != "" {
+		req.Header.Set(fiber.HeaderXRequestID, id)
+	}
+	return req, nil
+}
+
+// fetchSimulatorNodes retrieves the current node list from the request's
+// cluster's node-simulator
+func fetchSimulatorNodes(c *fiber.Ctx) ([]SimulatorNode, error) {
+	req, err := newSimulatorRequest(c, http.MethodGet, clusterFor(c).SimulatorURL+"/api/nodes", nil)
+	if err != nil {
+		return nil, fmt.Errorf("node simulator request failed: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("node simulator request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("node simulator returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Nodes []SimulatorNode `json:"nodes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode node simulator response: %w", err)
+	}
+	return payload.Nodes, nil
+}
+
+// lookupError is a failed lookup to be returned to the client as-is
+type lookupError struct {
+	status int
+	body   fiber.Map
+}
+
+// lookupGPU validates the :id/:index params against the live node: a
+// malformed index is a 400, an unknown node or out-of-range index a 404
+func lookupGPU(c *fiber.Ctx) (*SimulatorNode, int, *lookupError) {
+	index, verr := ValidateGPUIndex(c.Params("index"))
+	if verr != nil {
+		return nil, 0, &lookupError{fiber.StatusBadRequest, fiber.Map{
+			"error": verr.Message,
+			"field": verr.Field,
+		}}
+	}
+
+	nodeID := c.Params("id")
+	nodes, err := fetchSimulatorNodes(c)
+	if err != nil {
+		slog.Error("Failed to fetch nodes for GPU lookup", "error", err)
+		return nil, 0, &lookupError{fiber.StatusBadGateway, fiber.Map{
+			"error": "Node simulator unavailable",
+		}}
+	}
+
+	for i := range nodes {
+		if nodes[i].ID != nodeID {
+			continue
+		}
+		if index >= len(nodes[i].GPUs) {
+			return nil, 0, &lookupError{fiber.StatusNotFound, fiber.Map{
+				"error":     fmt.Sprintf("GPU index %d out of range: node %s has %d GPUs", index, nodeID, len(nodes[i].GPUs)),
+				"node_id":   nodeID,
+				"gpu_index": index,
+				"gpu_count": len(nodes[i].GPUs),
+			}}
+		}
+		return &nodes[i], index, nil
+	}
+
+	return nil, 0, &lookupError{fiber.StatusNotFound, fiber.Map{
+		"error":   "Node not found",
+		"node_id": nodeID,
+	}}
+}
+
+// proxyToNodeSimulator forwards the request to the node-simulator API
+func proxyToNodeSimulator(c *fiber.Ctx, method, path string) error {
+	url := fmt.Sprintf("%s%s", clusterFor(c).SimulatorURL, path)
+
+	var body io.Reader
+	if len(c.Body()) > 0 {
+		body = bytes.NewReader(c.Body())
+	}
+
+	req, err := newSimulatorRequest(c, method, url, body)
+	if err != nil {
+		slog.Error("Failed to create node simulator request", "error", err)
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": "Failed to create proxy request",
+		})
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		slog.Error("Node simulator proxy error",
+			"error", err,
+			"url", url,
+			"request_id", c.GetRespHeader(fiber.HeaderXRequestID),
+		)
+		return respond(c, fiber.StatusBadGateway, fiber.Map{
+			"error": "Node simulator unavailable",
+		})
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		slog.Error("Failed to read node simulator response", "error", err)
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": "Failed to read response",
+		})
+	}
+
+	c.Set("Content-Type", "application/json")
+	return respondRaw(c, resp.StatusCode, respBody)
+}
+
+// Inventory summarizes the hardware in the fleet
+type Inventory struct {
+	GPUsByModel       map[string]int `json:"gpus_by_model"`
+	GPUsTotal         int            `json:"gpus_total"`
+	GPUMemoryTotalGiB float64        `json:"gpu_memory_total_gib"`
+	GPUMaxPowerTotalW float64        `json:"gpu_max_power_total_w"`
+	NodesByType       map[string]int `json:"nodes_by_type"`
+	NodesTotal        int            `json:"nodes_total"`
+	GeneratedAt       time.Time      `json:"generated_at"`
+}
+
+// inventoryCacheTTL bounds how long inventory is served from cache; hardware
+// rarely changes so this can be long
+const inventoryCacheTTL = 5 * time.Minute
+
+// Inventory cache, keyed by cluster name
+var inventoryCache = newClusterCache[Inventory]()
+
+func buildInventory(nodes []SimulatorNode) *Inventory {
+	inv := &Inventory{
+		GPUsByModel: make(map[string]int),
+		NodesByType: make(map[string]int),
+		NodesTotal:  len(nodes),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, node := range nodes {
+		inv.NodesByType[node.Type]++
+		for _, gpu := range node.GPUs {
+			inv.GPUsByModel[gpu.Model]++
+			inv.GPUsTotal++
+			inv.GPUMemoryTotalGiB += gpu.MemoryTotalMiB / 1024
+			inv.GPUMaxPowerTotalW += gpu.MaxPowerW
+		}
+	}
+	return inv
+}
+
+// getInventory returns the GPU fleet inventory summary
+func getInventory(c *fiber.Ctx) error {
+	backend := clusterFor(c)
+
+	inv, hit, age, err := inventoryCache.get(backend.Name, inventoryCacheTTL, func() (*Inventory, bool, error) {
+		nodes, err := fetchSimulatorNodes(c)
+		if err != nil {
+			return nil, false, err
+		}
+		return buildInventory(nodes), true, nil
+	})
+	if err != nil {
+		slog.Error("Failed to fetch inventory", "cluster", backend.Name, "error", err)
+		if inv == nil {
+			return respond(c, fiber.StatusBadGateway, fiber.Map{
+				"error": "Node simulator unavailable",
+			})
+		}
+		// Serve stale inventory rather than failing
+	}
+	return respondCached(c, fiber.StatusOK, inv, hit, age)
+}
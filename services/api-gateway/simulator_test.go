@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetGPU_IndexOutOfRange(t *testing.T) {
+	app := newTestApp(t, "http://127.0.0.1:1")
+	unused := "http://127.0.0.1:1"
+	err := initClusters(ClusterBackend{
+		Name:          "primary",
+		PrometheusURL: unused,
+		SchedulerURL:  unused,
+		SimulatorURL:  fakeSimulator(t).URL,
+	}, "")
+	if err != nil {
+		t.Fatalf("initClusters: %v", err)
+	}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/api/v1/cluster/nodes/gpu-node-01/gpus/7", http.StatusOK, `"index"`},
+		{"/api/v1/cluster/nodes/gpu-node-01/gpus/8", http.StatusNotFound, `"gpu_count":8`},
+		{"/api/v1/cluster/nodes/gpu-node-01/gpus/99999", http.StatusNotFound, `"gpu_count":8`},
+		{"/api/v1/cluster/nodes/gpu-node-01/gpus/-1", http.StatusBadRequest, "non-negative integer"},
+		{"/api/v1/cluster/nodes/gpu-node-09/gpus/0", http.StatusNotFound, "Node not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			status, body := get(t, app, tt.path)
+			if status != tt.wantStatus || !strings.Contains(body, tt.wantBody) {
+				t.Errorf("status %d, body %s; want %d with %s", status, body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
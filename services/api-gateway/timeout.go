@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestTimeout is the overall deadline for a request; zero disables it
+var requestTimeout time.Duration
+
+// initRequestTimeout sets the deadline applied by RequestTimeoutMiddleware
+func initRequestTimeout(timeout time.Duration) {
+	requestTimeout = timeout
+	if timeout == 0 {
+		slog.Warn("Request timeout disabled")
+		return
+	}
+	slog.Info("Request timeout initialized", "timeout", timeout)
+}
+
+// isStreamingRequest reports whether the response outlives its handler.
+// Streams are held open for as long as the client reads, so they manage
+// their own deadlines instead of the request's.
+func isStreamingRequest(c *fiber.Ctx) bool {
+	path := strings.TrimSuffix(c.Path(), "/")
+	switch {
+	case path == "/api/v1/metrics/export", path == "/api/v1/ai/chat/stream", path == "/api/v1/ws/metrics",
+		path == "/api/v1/alerts/stream":
+		return true
+	case strings.HasPrefix(path, "/api/v1/jobs/") && strings.HasSuffix(path, "/logs"):
+		return c.QueryBool("follow")
+	case path == "/api/v1/jobs" && c.Method() == fiber.MethodPost:
+		return wantsEventStream(c)
+	}
+	return false
+}
+
+// RequestTimeoutMiddleware gives each request a context that expires after
+// requestTimeout. Upstream calls made with c.UserContext() are cancelled at
+// the deadline; a request that failed because of it gets a 504 with code
+// REQUEST_TIMEOUT in place of whatever error the handler produced.
+func RequestTimeoutMiddleware(c *fiber.Ctx) error {
+	if requestTimeout == 0 || isStreamingRequest(c) {
+		return c.Next()
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), requestTimeout)
+	defer cancel()
+	c.SetUserContext(ctx)
+
+	err := c.Next()
+
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	if err == nil && c.Response().StatusCode() < fiber.StatusInternalServerError {
+		// Finished successfully, just late; the result is still worth sending
+		return nil
+	}
+
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID)
+	slog.Warn("Request timed out",
+		"method", c.Method(),
+		"path", c.Path(),
+		"request_id", requestID,
+		"timeout", requestTimeout.String(),
+	)
+	return respond(c, fiber.StatusGatewayTimeout, fiber.Map{
+		"error":      "Request timed out",
+		"code":       "REQUEST_TIMEOUT",
+		"request_id": requestID,
+	})
+}
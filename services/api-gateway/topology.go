@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TopologyGPU is a GPU slot in the cluster map
+type TopologyGPU struct {
+	Index          int     `json:"index"`
+	Model          string  `json:"model"`
+	MemoryTotalMiB float64 `json:"memory_total_mib"`
+	MaxPowerW      float64 `json:"max_power_w"`
+}
+
+// TopologyNode is a node's fixed hardware, without live state
+type TopologyNode struct {
+	ID            string        `json:"id"`
+	Type          string        `json:"type"`
+	MemoryTotalGB float64       `json:"memory_total_gb"`
+	GPUCount      int           `json:"gpu_count"`
+	GPUs          []TopologyGPU `json:"gpus"`
+}
+
+// TopologyPartition is a scheduler partition's configured capacity
+type TopologyPartition struct {
+	Name           string  `json:"name"`
+	TotalNodes     int     `json:"total_nodes"`
+	TotalCPUs      int     `json:"total_cpus"`
+	TotalGPUs      int     `json:"total_gpus"`
+	TotalMemoryGB  float64 `json:"total_memory_gb"`
+	MaxTimeMinutes int     `json:"max_time_minutes"`
+}
+
+// Topology is the stable structure the cluster map is drawn onto. Live
+// status and utilization come from the other cluster endpoints.
+type Topology struct {
+	Cluster     string              `json:"cluster"`
+	Nodes       []TopologyNode      `json:"nodes"`
+	Partitions  []TopologyPartition `json:"partitions"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+// topologyCacheTTL matches the inventory; the layout only changes when the
+// simulator or scheduler is reconfigured
+const topologyCacheTTL = 5 * time.Minute
+
+// Topology cache, keyed by cluster name
+var topologyCache = newClusterCache[Topology]()
+
+// fetchSchedulerPartitions retrieves the partition list from the scheduler at baseURL
+func fetchSchedulerPartitions(ctx context.Context, baseURL string) ([]TopologyPartition, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/partitions", nil)
+	if err != nil {
+		return nil, fmt.Errorf("job scheduler request failed: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("job scheduler request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("job scheduler returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Partitions []TopologyPartition `json:"partitions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode job scheduler response: %w", err)
+	}
+	return payload.Partitions, nil
+}
+
+func buildTopology(clusterName string, nodes []SimulatorNode, partitions []TopologyPartition) *Topology {
+	topology := &Topology{
+		Cluster:     clusterName,
+		Nodes:       make([]TopologyNode, 0, len(nodes)),
+		Partitions:  partitions,
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, node := range nodes {
+		entry := TopologyNode{
+			ID:            node.ID,
+			Type:          node.Type,
+			MemoryTotalGB: node.MemoryTotalGB,
+			GPUCount:      len(node.GPUs),
+			GPUs:          make([]TopologyGPU, 0, len(node.GPUs)),
+		}
+		for _, gpu := range node.GPUs {
+			entry.GPUs = append(entry.GPUs, TopologyGPU{
+				Index:          gpu.Index,
+				Model:          gpu.Model,
+				MemoryTotalMiB: gpu.MemoryTotalMiB,
+				MaxPowerW:      gpu.MaxPowerW,
+			})
+		}
+		topology.Nodes = append(topology.Nodes, entry)
+	}
+	return topology
+}
+
+// getTopology returns the cluster's nodes, GPUs and partitions in one call.
+// A topology missing its partitions is served but not cached, so the next
+// request retries the scheduler.
+func getTopology(c *fiber.Ctx) error {
+	backend := clusterFor(c)
+
+	topology, hit, age, err := topologyCache.get(backend.Name, topologyCacheTTL, func() (*Topology, bool, error) {
+		nodes, err := fetchSimulatorNodes(c)
+		if err != nil {
+			return nil, false, err
+		}
+		partitions, err := fetchSchedulerPartitions(c.UserContext(), backend.SchedulerURL)
+		if err != nil {
+			slog.Warn("Topology served without partitions", "cluster", backend.Name, "error", err)
+			return buildTopology(backend.Name, nodes, nil), false, nil
+		}
+		return buildTopology(backend.Name, nodes, partitions), true, nil
+	})
+	if err != nil {
+		slog.Error("Failed to fetch topology", "cluster", backend.Name, "error", err)
+		if topology == nil {
+			return respond(c, fiber.StatusBadGateway, fiber.Map{
+				"error": "Node simulator unavailable",
+			})
+		}
+		// Serve the stale layout rather than failing
+	}
+	return respondCached(c, fiber.StatusOK, topology, hit, age)
+}
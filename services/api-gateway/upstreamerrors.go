@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxUpstreamErrorText bounds a non-JSON upstream error body copied into the envelope
+const maxUpstreamErrorText = 512
+
+// wrapSchedulerErrors re-wraps scheduler 4xx/5xx bodies in the gateway's
+// error shape; when false they are forwarded verbatim
+var wrapSchedulerErrors bool
+
+func initUpstreamErrors(wrap bool) {
+	wrapSchedulerErrors = wrap
+	slog.Info("Scheduler error handling initialized", "wrap", wrap)
+}
+
+// upstreamError converts an upstream error response into the gateway's
+// {"error": ...} shape, keeping the original message. FastAPI-style
+// {"detail": "..."} and {"error": "..."} bodies are understood; structured
+// details such as validation errors are carried under "details".
+func upstreamError(source string, status int, body []byte) fiber.Map {
+	result := fiber.Map{
+		"error":  http.StatusText(status),
+		"source": source,
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		switch detail := parsed["detail"].(type) {
+		case string:
+			result["error"] = detail
+		case nil:
+			if message, ok := parsed["error"].(string); ok && message != "" {
+				result["error"] = message
+			}
+		default:
+			result["details"] = detail
+		}
+	} else if text := strings.TrimSpace(string(body)); text != "" {
+		if len(text) > maxUpstreamErrorText {
+			text = text[:maxUpstreamErrorText]
+		}
+		result["error"] = text
+	}
+	return result
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,12 +19,81 @@ const (
 	MaxIDLen       = 64
 	MaxQueryLen    = 500
 	MaxMessageLen  = 10000
+	MaxPageLimit   = 1000
+	MaxWallTimeMin = 43200 // 30 days, hard ceiling for every partition
 )
 
+// bodySoftLimit is the body size above which requests are logged and
+// counted but still served, to spot clients trending toward MaxBodySize.
+// Zero disables it.
+var bodySoftLimit int
+
+// initBodySoftLimit sets the soft body limit, which must sit below the hard one
+func initBodySoftLimit(limit int) error {
+	if limit >= MaxBodySize {
+		return fmt.Errorf("soft limit %d must be below the %d byte hard limit", limit, MaxBodySize)
+	}
+	bodySoftLimit = limit
+	slog.Info("Request body soft limit initialized", "soft_limit", limit, "hard_limit", MaxBodySize)
+	return nil
+}
+
+// Per-partition wall-time limits in minutes, loaded from config and
+// replaced on reload
+var (
+	partitionMaxWallTime      = map[string]int{}
+	partitionMaxWallTimeMutex = &sync.RWMutex{}
+)
+
+// initPartitionLimits parses a "partition=minutes,..." spec into the
+// per-partition wall-time limits. Malformed entries are skipped with a warning.
+func initPartitionLimits(spec string) {
+	limits := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		minutes, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || minutes <= 0 {
+			slog.Warn("Ignoring invalid partition wall-time limit", "entry", entry)
+			continue
+		}
+		limits[strings.TrimSpace(name)] = minutes
+	}
+	partitionMaxWallTimeMutex.Lock()
+	partitionMaxWallTime = limits
+	partitionMaxWallTimeMutex.Unlock()
+	slog.Info("Partition wall-time limits initialized", "limits", limits)
+}
+
+// partitionWallTimeLimit returns the wall-time limit for a partition, if any
+func partitionWallTimeLimit(partition string) (int, bool) {
+	partitionMaxWallTimeMutex.RLock()
+	defer partitionMaxWallTimeMutex.RUnlock()
+	limit, ok := partitionMaxWallTime[partition]
+	return limit, ok
+}
+
+// ValidatePartitionWallTime rejects a wall time above the partition's limit,
+// naming the limit
+func ValidatePartitionWallTime(partition string, minutes int, field string) *ValidationError {
+	limit, ok := partitionWallTimeLimit(partition)
+	if !ok || minutes <= limit {
+		return nil
+	}
+	return &ValidationError{
+		Field:   field,
+		Message: fmt.Sprintf("Wall time exceeds the %s partition limit of %d minutes", partition, limit),
+	}
+}
+
 var (
 	// Safe patterns for various inputs
 	safeIDPattern      = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 	safeNamePattern    = regexp.MustCompile(`^[a-zA-Z0-9_\-\s\.]+$`)
+	safeUserPattern    = regexp.MustCompile(`^[a-zA-Z0-9_\-\.@+]+$`)
 	dangerousPatterns  = []string{
 		"<script", "</script>",
 		"javascript:", "onerror=",
@@ -65,6 +137,39 @@ func ValidateName(name string) *ValidationError {
 	return nil
 }
 
+// ValidateGPUIndex parses a GPU index path parameter. Bounds against the
+// node's actual GPU count are checked once the node is known.
+func ValidateGPUIndex(raw string) (int, *ValidationError) {
+	if raw == "" || strings.Trim(raw, "0123456789") != "" {
+		return 0, &ValidationError{Field: "index", Message: "GPU index must be a non-negative integer"}
+	}
+	index, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, &ValidationError{Field: "index", Message: "GPU index is too large"}
+	}
+	return index, nil
+}
+
+// ValidatePagination parses optional limit and offset query params. A
+// missing limit is returned as 0, meaning no limit.
+func ValidatePagination(limitRaw, offsetRaw string) (limit, offset int, verr *ValidationError) {
+	if limitRaw != "" {
+		n, err := strconv.Atoi(limitRaw)
+		if err != nil || n < 1 || n > MaxPageLimit {
+			return 0, 0, &ValidationError{Field: "limit", Message: fmt.Sprintf("limit must be an integer between 1 and %d", MaxPageLimit)}
+		}
+		limit = n
+	}
+	if offsetRaw != "" {
+		n, err := strconv.Atoi(offsetRaw)
+		if err != nil || n < 0 {
+			return 0, 0, &ValidationError{Field: "offset", Message: "offset must be a non-negative integer"}
+		}
+		offset = n
+	}
+	return limit, offset, nil
+}
+
 // ValidateMessage validates user message input (for AI chat)
 func ValidateMessage(msg string) *ValidationError {
 	if msg == "" {
@@ -79,6 +184,32 @@ func ValidateMessage(msg string) *ValidationError {
 	return nil
 }
 
+// ValidateNote validates an optional free-text note such as an ack reason
+func ValidateNote(note string) *ValidationError {
+	if len(note) > MaxMessageLen {
+		return &ValidationError{Field: "note", Message: "Note exceeds maximum length"}
+	}
+	if !utf8.ValidString(note) {
+		return &ValidationError{Field: "note", Message: "Note contains invalid UTF-8"}
+	}
+	return nil
+}
+
+// ValidateAckUser validates the optional user an acknowledgement is
+// recorded for: a username or email address
+func ValidateAckUser(user string) *ValidationError {
+	if user == "" {
+		return nil
+	}
+	if len(user) > MaxIDLen*4 {
+		return &ValidationError{Field: "by", Message: "User exceeds maximum length"}
+	}
+	if !safeUserPattern.MatchString(user) {
+		return &ValidationError{Field: "by", Message: "User contains invalid characters"}
+	}
+	return nil
+}
+
 // SanitizeString removes potentially dangerous content
 func SanitizeString(s string) string {
 	result := s
@@ -125,6 +256,16 @@ func InputValidationMiddleware(c *fiber.Ctx) error {
 				"max":   MaxBodySize,
 			})
 		}
+		if bodySoftLimit > 0 && len(c.Body()) > bodySoftLimit {
+			slog.Warn("Request body over soft limit",
+				"size", len(c.Body()),
+				"soft_limit", bodySoftLimit,
+				"max", MaxBodySize,
+				"method", c.Method(),
+				"path", c.Path(),
+			)
+			bodySoftLimitExceededTotal.WithLabelValues(strings.Clone(c.Method())).Inc()
+		}
 	}
 
 	// Validate path parameters
@@ -157,6 +298,7 @@ type JobRequest struct {
 	GPUs            int    `json:"gpus"`
 	MemoryGB        int    `json:"memory_gb"`
 	WallTimeMinutes int    `json:"wall_time_minutes"`
+	MultiNode       bool   `json:"multi_node"` // GPUs may span nodes; memory_gb is per node
 }
 
 func (j *JobRequest) Validate() []ValidationError {
@@ -206,11 +348,13 @@ func (j *JobRequest) Validate() []ValidationError {
 		})
 	}
 
-	if j.WallTimeMinutes < 0 || j.WallTimeMinutes > 43200 {
+	if j.WallTimeMinutes < 0 || j.WallTimeMinutes > MaxWallTimeMin {
 		errors = append(errors, ValidationError{
 			Field:   "wall_time_minutes",
 			Message: "Wall time must be between 0 and 43200 minutes (30 days)",
 		})
+	} else if err := ValidatePartitionWallTime(j.Partition, j.WallTimeMinutes, "wall_time_minutes"); err != nil {
+		errors = append(errors, *err)
 	}
 
 	return errors
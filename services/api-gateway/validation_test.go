@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateGPUIndex(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr string // Empty means the index must parse
+	}{
+		{"0", 0, ""},
+		{"7", 7, ""},
+		{"99999", 99999, ""}, // Valid; the bounds check 404s it
+		{"", 0, "non-negative integer"},
+		{"-1", 0, "non-negative integer"},
+		{"+1", 0, "non-negative integer"},
+		{"1a", 0, "non-negative integer"},
+		{"99999999999999999999", 0, "too large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, verr := ValidateGPUIndex(tt.raw)
+			if tt.wantErr == "" {
+				if verr != nil || got != tt.want {
+					t.Errorf("ValidateGPUIndex(%q) = %d, %v; want %d", tt.raw, got, verr, tt.want)
+				}
+				return
+			}
+			if verr == nil || !strings.Contains(verr.Message, tt.wantErr) {
+				t.Errorf("ValidateGPUIndex(%q) = %d, %v; want an error saying %q", tt.raw, got, verr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// How often live metrics are pushed, and how long a push may block on a
+// client that has stopped reading
+const (
+	wsMetricsInterval     = time.Second
+	wsMetricsWriteTimeout = 5 * time.Second
+)
+
+// nodeUtilizationQueries maps each per-node field of a live snapshot to the
+// PromQL that yields it, one series per node
+var nodeUtilizationQueries = map[string]string{
+	"up":                 "pulse_node_up",
+	"cpu_utilization":    "pulse_cpu_utilization",
+	"memory_utilization": "pulse_memory_utilization",
+	"gpu_utilization":    "pulse_node_gpu_utilization_avg",
+}
+
+// Connected live metrics clients, deregistered when their connection ends
+var (
+	wsClients      = make(map[*websocket.Conn]struct{})
+	wsClientsMutex = &sync.Mutex{}
+	wsMaxClients   int
+)
+
+// liveSnapshot is the latest snapshot built for one cluster
+type liveSnapshot struct {
+	mu    sync.Mutex
+	at    time.Time
+	frame []byte
+}
+
+// Snapshots by cluster name, shared by every client watching that cluster
+var (
+	liveSnapshots      = make(map[string]*liveSnapshot)
+	liveSnapshotsMutex = &sync.Mutex{}
+)
+
+// initWSMetrics sets how many live metrics connections may be open at once
+func initWSMetrics(maxClients int) {
+	wsMaxClients = maxClients
+	slog.Info("Live metrics WebSocket initialized", "max_clients", maxClients, "interval", wsMetricsInterval.String())
+}
+
+// registerWSClient adds a connection unless the limit has been reached
+func registerWSClient(conn *websocket.Conn) bool {
+	wsClientsMutex.Lock()
+	defer wsClientsMutex.Unlock()
+	if len(wsClients) >= wsMaxClients {
+		return false
+	}
+	wsClients[conn] = struct{}{}
+	wsClientsConnected.Set(float64(len(wsClients)))
+	return true
+}
+
+// deregisterWSClient removes a connection that has ended
+func deregisterWSClient(conn *websocket.Conn) {
+	wsClientsMutex.Lock()
+	defer wsClientsMutex.Unlock()
+	delete(wsClients, conn)
+	wsClientsConnected.Set(float64(len(wsClients)))
+}
+
+// nodeUtilization reads each node's state and utilization from Prometheus,
+// sorted by node. gpu_utilization is only present for GPU nodes.
+func nodeUtilization(ctx context.Context, promURL string) ([]fiber.Map, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		nodes    = make(map[string]fiber.Map)
+		firstErr error
+	)
+	for field, query := range nodeUtilizationQueries {
+		wg.Add(1)
+		go func(field, query string) {
+			defer wg.Done()
+			samples, err := queryVector(ctx, promURL, query)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			for _, sample := range samples {
+				id := sample.metric["node"]
+				if id == "" {
+					continue
+				}
+				if nodes[id] == nil {
+					nodes[id] = fiber.Map{"node": id, "node_type": sample.metric["node_type"]}
+				}
+				if field == "up" {
+					nodes[id][field] = sample.value == 1
+				} else {
+					nodes[id][field] = sample.value
+				}
+			}
+		}(field, query)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	list := make([]fiber.Map, 0, len(nodes))
+	for _, node := range nodes {
+		list = append(list, node)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i]["node"].(string) < list[j]["node"].(string)
+	})
+	return list, nil
+}
+
+// buildLiveSnapshot queries a cluster's status, from the same helper as
+// GET /cluster/status, and its per-node utilization
+func buildLiveSnapshot(backend *ClusterBackend) []byte {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterStatusTimeout)
+	defer cancel()
+
+	snapshot := fiber.Map{
+		"time":    time.Now().UTC(),
+		"cluster": backend.Name,
+		"status":  clusterStatus(ctx, backend.PrometheusURL),
+	}
+	nodes, err := nodeUtilization(ctx, backend.PrometheusURL)
+	if err != nil {
+		slog.Warn("Live metrics node query failed", "cluster", backend.Name, "error", err)
+		snapshot["nodes"] = nil
+		snapshot["nodes_error"] = "Prometheus unavailable"
+	} else {
+		snapshot["nodes"] = nodes
+	}
+	frame, _ := json.Marshal(snapshot)
+	return frame
+}
+
+// currentSnapshot returns a cluster's snapshot, rebuilding it at most once
+// per interval however many clients are connected
+func currentSnapshot(backend *ClusterBackend) []byte {
+	liveSnapshotsMutex.Lock()
+	snapshot, ok := liveSnapshots[backend.Name]
+	if !ok {
+		snapshot = &liveSnapshot{}
+		liveSnapshots[backend.Name] = snapshot
+	}
+	liveSnapshotsMutex.Unlock()
+
+	snapshot.mu.Lock()
+	defer snapshot.mu.Unlock()
+	// A little slack so clients ticking together share one build
+	if time.Since(snapshot.at) >= wsMetricsInterval*9/10 {
+		snapshot.frame = buildLiveSnapshot(backend)
+		snapshot.at = time.Now()
+	}
+	return snapshot.frame
+}
+
+// upgradeWSMetrics admits a WebSocket upgrade for live metrics, answering
+// 426 to plain HTTP requests and 503 with code WS_CONNECTION_LIMIT when
+// WS_MAX_CONNECTIONS clients are already connected
+func upgradeWSMetrics(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return respond(c, fiber.StatusUpgradeRequired, fiber.Map{
+			"error": "WebSocket upgrade required",
+			"code":  "UPGRADE_REQUIRED",
+		})
+	}
+	wsClientsMutex.Lock()
+	full := len(wsClients) >= wsMaxClients
+	wsClientsMutex.Unlock()
+	if full {
+		return respond(c, fiber.StatusServiceUnavailable, fiber.Map{
+			"error": "Too many live metrics connections",
+			"code":  "WS_CONNECTION_LIMIT",
+			"limit": wsMaxClients,
+		})
+	}
+	return c.Next()
+}
+
+// streamWSMetrics pushes a JSON snapshot of cluster status and per-node
+// utilization every wsMetricsInterval until the client disconnects.
+// Messages from the client are read only to notice the close.
+func streamWSMetrics(conn *websocket.Conn) {
+	if !registerWSClient(conn) {
+		// Lost a race for the last slot after the upgrade check
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too many live metrics connections"),
+			time.Now().Add(wsMetricsWriteTimeout))
+		return
+	}
+	defer deregisterWSClient(conn)
+
+	backend, ok := conn.Locals(clusterLocalsKey).(*ClusterBackend)
+	if !ok {
+		backend = primaryCluster
+	}
+	slog.Debug("Live metrics client connected", "cluster", backend.Name, "remote", conn.RemoteAddr().String())
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(wsMetricsInterval)
+	defer ticker.Stop()
+	for {
+		conn.SetWriteDeadline(time.Now().Add(wsMetricsWriteTimeout))
+		if err := conn.WriteMessage(websocket.TextMessage, currentSnapshot(backend)); err != nil {
+			slog.Debug("Live metrics client went away", "cluster", backend.Name, "error", err)
+			return
+		}
+		select {
+		case <-closed:
+			slog.Debug("Live metrics client disconnected", "cluster", backend.Name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
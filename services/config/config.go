@@ -0,0 +1,217 @@
+// Package config loads service configuration from environment variables
+// into a tagged struct, applying defaults and validating every field.
+//
+// Fields are described with struct tags:
+//
+//	Port string `env:"PORT" default:"8081" validate:"port"`
+//
+// Supported field types are string, bool, int, int64, float64 and
+// time.Duration. Supported validate rules are "required", "port", "url",
+// "min=N" and "max=N", separated by commas. Duration bounds are given in
+// seconds.
+//
+// Fields tagged reload:"hot" may be changed on a running service; see Reload.
+// LoadEnvFile fills the environment from a KEY=VALUE file before Load runs.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error lists every problem found while loading a config struct
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(e.Problems, "; "))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load populates the struct pointed to by dst from the environment, falling
+// back to each field's default tag, then validates it. All problems are
+// collected and returned together as an *Error.
+func Load(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: Load requires a pointer to a struct, got %T", dst)
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	var problems []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("env")
+		if key == "" || !field.IsExported() {
+			continue
+		}
+
+		raw, ok := os.LookupEnv(key)
+		if !ok || raw == "" {
+			raw = field.Tag.Get("default")
+		}
+
+		if err := setField(v.Field(i), raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		for _, err := range validateField(v.Field(i), raw, field.Tag.Get("validate")) {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &Error{Problems: problems}
+	}
+	return nil
+}
+
+// Reload compares a freshly loaded config against the current one. Changed
+// fields tagged reload:"hot" are copied into current and their env keys
+// returned as applied; any other changed field is left untouched and its key
+// returned as needing a restart. Both arguments must point to the same struct
+// type.
+func Reload(current, next interface{}) (applied, restart []string, err error) {
+	cur, nxt := reflect.ValueOf(current), reflect.ValueOf(next)
+	if cur.Kind() != reflect.Ptr || cur.Elem().Kind() != reflect.Struct || cur.Type() != nxt.Type() {
+		return nil, nil, fmt.Errorf("config: Reload requires two pointers to the same struct type, got %T and %T", current, next)
+	}
+	cur, nxt = cur.Elem(), nxt.Elem()
+	t := cur.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("env")
+		if key == "" || !field.IsExported() {
+			continue
+		}
+		if reflect.DeepEqual(cur.Field(i).Interface(), nxt.Field(i).Interface()) {
+			continue
+		}
+
+		if field.Tag.Get("reload") == "hot" {
+			cur.Field(i).Set(nxt.Field(i))
+			applied = append(applied, key)
+		} else {
+			restart = append(restart, key)
+		}
+	}
+	return applied, restart, nil
+}
+
+func setField(f reflect.Value, raw string) error {
+	if f.Type() == durationType {
+		if raw == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(raw)
+	case reflect.Bool:
+		if raw == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		if raw == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.SetInt(n)
+	case reflect.Float64:
+		if raw == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		f.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+	return nil
+}
+
+func validateField(f reflect.Value, raw, rules string) []error {
+	if rules == "" {
+		return nil
+	}
+
+	var errs []error
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			if raw == "" {
+				errs = append(errs, fmt.Errorf("is required"))
+			}
+		case "port":
+			port, err := strconv.Atoi(raw)
+			if err != nil || port < 1 || port > 65535 {
+				errs = append(errs, fmt.Errorf("invalid port %q", raw))
+			}
+		case "url":
+			u, err := url.Parse(raw)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				errs = append(errs, fmt.Errorf("invalid URL %q", raw))
+			}
+		case "min", "max":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("bad %s rule %q", name, arg))
+				continue
+			}
+			value, ok := numericValue(f)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s rule on non-numeric field", name))
+				continue
+			}
+			if name == "min" && value < limit {
+				errs = append(errs, fmt.Errorf("must be at least %s, got %s", arg, raw))
+			}
+			if name == "max" && value > limit {
+				errs = append(errs, fmt.Errorf("must be at most %s, got %s", arg, raw))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("unknown validate rule %q", name))
+		}
+	}
+	return errs
+}
+
+func numericValue(f reflect.Value) (float64, bool) {
+	if f.Type() == durationType {
+		return time.Duration(f.Int()).Seconds(), true
+	}
+	switch f.Kind() {
+	case reflect.Int, reflect.Int64:
+		return float64(f.Int()), true
+	case reflect.Float64:
+		return f.Float(), true
+	}
+	return 0, false
+}
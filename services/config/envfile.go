@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// envFileKeys records the variables set from the config file, so a reload can
+// replace them without overriding the real environment
+var envFileKeys = make(map[string]bool)
+
+// LoadEnvFile loads KEY=VALUE pairs from the file named by CONFIG_FILE (or
+// .env by default) into the process environment. Variables already set in
+// the real environment take precedence over file values. Calling it again
+// re-reads the file, replacing values it set before and unsetting any that
+// were removed.
+func LoadEnvFile() {
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit || path == "" {
+		path = ".env"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		// A missing default .env is normal; a missing explicit file is not
+		if explicit || !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("Failed to open config file", "path", path, "error", err)
+		}
+		return
+	}
+	defer file.Close()
+
+	loaded := 0
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			slog.Warn("Ignoring malformed config line", "path", path, "line", lineNum)
+			continue
+		}
+		value = unquote(strings.TrimSpace(value))
+
+		if _, set := os.LookupEnv(key); set && !envFileKeys[key] {
+			continue
+		}
+		os.Setenv(key, value)
+		envFileKeys[key] = true
+		seen[key] = true
+		loaded++
+	}
+	if err := scanner.Err(); err != nil {
+		slog.Warn("Failed to read config file", "path", path, "error", err)
+		return
+	}
+
+	for key := range envFileKeys {
+		if !seen[key] {
+			os.Unsetenv(key)
+			delete(envFileKeys, key)
+		}
+	}
+
+	slog.Info("Config file loaded", "path", path, "variables", loaded)
+}
+
+// unquote strips one pair of matching surrounding quotes
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
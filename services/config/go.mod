@@ -0,0 +1,3 @@
+module github.com/pulse/config
+
+go 1.23.5
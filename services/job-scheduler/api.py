@@ -5,6 +5,7 @@ import logging
 from typing import Optional
 
 from fastapi import APIRouter, HTTPException, Query
+from fastapi.responses import PlainTextResponse, StreamingResponse
 
 from models import (
     Job, JobState, JobSubmission, JobResponse, JobListResponse,
@@ -92,6 +93,35 @@ async def get_job(job_id: str):
     return JobResponse(job=job)
 
 
+@router.get("/jobs/{job_id}/logs", response_class=PlainTextResponse)
+async def get_job_logs(
+    job_id: str,
+    tail: Optional[int] = Query(None, ge=1, le=10000, description="Only the last N lines"),
+    follow: bool = Query(False, description="Stream new lines until the job finishes"),
+):
+    """
+    Get a job's output, one line per log entry.
+
+    With follow=true the response is streamed and stays open until the job
+    reaches a terminal state.
+    """
+    if not scheduler:
+        raise HTTPException(status_code=503, detail="Scheduler not initialized")
+
+    lines = await scheduler.get_job_logs(job_id, tail)
+    if lines is None:
+        raise HTTPException(status_code=404, detail=f"Job {job_id} not found")
+
+    if not follow:
+        return PlainTextResponse("".join(f"{line}\n" for line in lines))
+
+    async def stream():
+        async for line in scheduler.follow_job_logs(job_id, tail):
+            yield f"{line}\n"
+
+    return StreamingResponse(stream(), media_type="text/plain")
+
+
 @router.delete("/jobs/{job_id}", response_model=JobResponse)
 async def cancel_job(job_id: str):
     """
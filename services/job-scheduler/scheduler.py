@@ -6,7 +6,7 @@ import asyncio
 import logging
 import time
 import uuid
-from collections import defaultdict
+from collections import defaultdict, deque
 from datetime import datetime, timedelta
 from typing import Optional
 
@@ -18,6 +18,36 @@ import metrics
 
 logger = logging.getLogger(__name__)
 
+# Per-job log retention; older lines are dropped once a job exceeds this
+MAX_JOB_LOG_LINES = 5000
+
+# Seconds between the progress lines a running job writes to its log
+JOB_LOG_PROGRESS_INTERVAL = 5
+
+# How often a follower polls for new log lines
+JOB_LOG_POLL_SECONDS = 0.5
+
+TERMINAL_STATES = (JobState.COMPLETED, JobState.FAILED, JobState.CANCELLED, JobState.TIMEOUT)
+
+
+class JobLog:
+    """Bounded log buffer for one job."""
+
+    def __init__(self):
+        self.lines: deque[str] = deque(maxlen=MAX_JOB_LOG_LINES)
+        # Lines ever written, so followers can resume after old lines are dropped
+        self.written = 0
+        self.progress_step = 0
+
+    def append(self, message: str):
+        self.lines.append(f"{datetime.utcnow().isoformat(timespec='seconds')}Z {message}")
+        self.written += 1
+
+    def since(self, position: int) -> list[str]:
+        """Lines written at or after an absolute position."""
+        first = self.written - len(self.lines)
+        return list(self.lines)[max(position - first, 0):]
+
 
 class JobScheduler:
     """
@@ -39,6 +69,9 @@ class JobScheduler:
         self._jobs_by_account: dict[str, set[str]] = defaultdict(set)
         self._jobs_by_partition: dict[str, set[str]] = defaultdict(set)
 
+        # Simulated job output
+        self._job_logs: dict[str, JobLog] = defaultdict(JobLog)
+
         # Completed jobs history (last 24h)
         self._completed_jobs: list[tuple[datetime, Job]] = []
 
@@ -181,6 +214,13 @@ class JobScheduler:
                         await self._transition_job(job, JobState.FAILED, exit_code=1)
                         metrics.slurm_jobs_failed_total.inc()
 
+            if job.state == JobState.RUNNING:
+                log = self._job_logs[job_id]
+                step = int(runtime) // JOB_LOG_PROGRESS_INTERVAL
+                if step > log.progress_step:
+                    log.progress_step = step
+                    log.append(f"step {step}: {int(runtime)}s elapsed of {time_limit}s limit")
+
     async def _schedule_pending_jobs(self):
         """Schedule pending jobs to available resources."""
         pending_jobs = [
@@ -243,6 +283,7 @@ class JobScheduler:
         wait_time = (now - job.submit_time).total_seconds()
         metrics.slurm_job_wait_time_seconds.observe(wait_time)
 
+        self._job_logs[job.id].append(f"Started on {job.node_id} after {wait_time:.0f}s in queue: {job.command}")
         logger.info(f"Job {job.id} ({job.name}) started on {job.node_id}")
 
     async def _transition_job(
@@ -286,6 +327,10 @@ class JobScheduler:
                 (t, j) for t, j in self._completed_jobs if t > cutoff
             ]
 
+        if exit_code is not None:
+            self._job_logs[job.id].append(f"Job {new_state.value} with exit code {exit_code}")
+        else:
+            self._job_logs[job.id].append(f"Job {new_state.value}")
         logger.info(f"Job {job.id} transitioned: {old_state} -> {new_state}")
 
     def _update_all_metrics(self):
@@ -430,6 +475,7 @@ class JobScheduler:
             # Update partition
             partition.jobs_pending += 1
 
+            self._job_logs[job_id].append(f"Submitted to partition {job.partition}")
             metrics.slurm_jobs_submitted_total.inc()
             logger.info(f"Job {job_id} ({job.name}) submitted to partition {job.partition}")
 
@@ -439,6 +485,28 @@ class JobScheduler:
         """Get a job by ID."""
         return self.jobs.get(job_id)
 
+    async def get_job_logs(self, job_id: str, tail: Optional[int] = None) -> Optional[list[str]]:
+        """Get a job's log lines, optionally only the last `tail`."""
+        if job_id not in self.jobs:
+            return None
+        lines = self._job_logs[job_id].since(0)
+        return lines[-tail:] if tail else lines
+
+    async def follow_job_logs(self, job_id: str, tail: Optional[int] = None):
+        """Yield a job's log lines as they are written until the job finishes."""
+        log = self._job_logs[job_id]
+        position = max(log.written - tail, 0) if tail else 0
+        while True:
+            # Read the state first so lines written by the final transition
+            # are still delivered before the stream ends
+            finished = self.jobs[job_id].state in TERMINAL_STATES
+            lines, position = log.since(position), log.written
+            for line in lines:
+                yield line
+            if finished:
+                return
+            await asyncio.sleep(JOB_LOG_POLL_SECONDS)
+
     async def list_jobs(
         self,
         state: Optional[JobState] = None,
@@ -468,7 +536,7 @@ class JobScheduler:
             if not job:
                 return None
 
-            if job.state in (JobState.COMPLETED, JobState.FAILED, JobState.CANCELLED, JobState.TIMEOUT):
+            if job.state in TERMINAL_STATES:
                 return job  # Already terminal
 
             await self._transition_job(job, JobState.CANCELLED)
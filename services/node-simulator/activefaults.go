@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Fault types, named after the endpoints that inject them
+const (
+	faultGPUEject       = "gpu-eject"
+	faultIBLinkDown     = "ib-link-down"
+	faultGPUFloor       = "gpu-floor"
+	faultMemoryPressure = "memory-pressure"
+)
+
+// ActiveFault is one fault currently in effect. IDs are derived from the
+// target ("gpu-eject:gpu-node-01:3", "memory-pressure:cpu-node-02"), so the
+// same fault keeps its ID until it is cleared.
+type ActiveFault struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Node      string     `json:"node"`
+	GPUIndex  *int       `json:"gpu_index,omitempty"`
+	Port      *int       `json:"port,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	RecoverAt *time.Time `json:"recovers_at,omitempty"`
+	// Remaining is null for faults that last until cleared
+	Remaining *int    `json:"remaining_seconds"`
+	XID       int     `json:"xid,omitempty"`
+	Floor     float64 `json:"floor,omitempty"`
+	Target    float64 `json:"target,omitempty"`
+}
+
+// until fills in the recovery time and seconds left for a self-clearing fault
+func (f *ActiveFault) until(recoverAt, now time.Time) {
+	if recoverAt.IsZero() {
+		return
+	}
+	remaining := int(math.Ceil(max(recoverAt.Sub(now), 0).Seconds()))
+	f.RecoverAt = &recoverAt
+	f.Remaining = &remaining
+}
+
+// ActiveFaults returns every fault in effect, oldest first
+func (c *Cluster) ActiveFaults() []ActiveFault {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	faults := []ActiveFault{}
+	for _, node := range c.Nodes {
+		node.mu.RLock()
+		for _, gpu := range node.GPUs {
+			index := gpu.Index
+			if gpu.Ejected {
+				fault := ActiveFault{
+					ID:        fmt.Sprintf("%s:%s:%d", faultGPUEject, node.ID, index),
+					Type:      faultGPUEject,
+					Node:      node.ID,
+					GPUIndex:  &index,
+					StartedAt: gpu.EjectedAt,
+					XID:       gpu.XIDCode,
+				}
+				fault.until(gpu.RecoverAt, now)
+				faults = append(faults, fault)
+			}
+			if gpu.UtilFloor > 0 {
+				faults = append(faults, ActiveFault{
+					ID:        fmt.Sprintf("%s:%s:%d", faultGPUFloor, node.ID, index),
+					Type:      faultGPUFloor,
+					Node:      node.ID,
+					GPUIndex:  &index,
+					StartedAt: gpu.FloorSince,
+					Floor:     gpu.UtilFloor,
+				})
+			}
+		}
+		for _, port := range node.IBPorts {
+			if port.Up {
+				continue
+			}
+			number := port.Number
+			fault := ActiveFault{
+				ID:        fmt.Sprintf("%s:%s:%d", faultIBLinkDown, node.ID, number),
+				Type:      faultIBLinkDown,
+				Node:      node.ID,
+				Port:      &number,
+				StartedAt: port.DownSince,
+			}
+			fault.until(port.RecoverAt, now)
+			faults = append(faults, fault)
+		}
+		if node.MemoryPressure > 0 {
+			faults = append(faults, ActiveFault{
+				ID:        fmt.Sprintf("%s:%s", faultMemoryPressure, node.ID),
+				Type:      faultMemoryPressure,
+				Node:      node.ID,
+				StartedAt: node.PressureSince,
+				Target:    node.MemoryPressure,
+			})
+		}
+		node.mu.RUnlock()
+	}
+
+	sort.Slice(faults, func(i, j int) bool {
+		if !faults[i].StartedAt.Equal(faults[j].StartedAt) {
+			return faults[i].StartedAt.Before(faults[j].StartedAt)
+		}
+		return faults[i].ID < faults[j].ID
+	})
+	return faults
+}
+
+// ClearFault ends an active fault early through the same path that would
+// lift it by hand: recovery for an ejected GPU, link-up for an IB port and a
+// zero floor or pressure target. ok is false when no such fault is active.
+func (c *Cluster) ClearFault(ctx context.Context, id string) (fault ActiveFault, ok bool, err error) {
+	for _, active := range c.ActiveFaults() {
+		if active.ID == id {
+			fault, ok = active, true
+			break
+		}
+	}
+	if !ok {
+		return fault, false, nil
+	}
+
+	switch fault.Type {
+	case faultGPUEject:
+		err = c.RecoverGPU(ctx, fault.Node, *fault.GPUIndex)
+	case faultIBLinkDown:
+		err = c.SetIBLink(ctx, fault.Node, *fault.Port, true, 0)
+	case faultGPUFloor:
+		err = c.SetGPUFloor(ctx, fault.Node, *fault.GPUIndex, 0)
+	case faultMemoryPressure:
+		err = c.SetMemoryPressure(ctx, fault.Node, 0)
+	}
+	return fault, true, err
+}
+
+// HandleListFaults handles GET /api/faults
+func (c *Cluster) HandleListFaults(w http.ResponseWriter, r *http.Request) {
+	faults := c.ActiveFaults()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"faults": faults,
+		"total":  len(faults),
+	})
+}
+
+// HandleClearFault handles DELETE /api/faults/{id}
+func (c *Cluster) HandleClearFault(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	fault, ok, err := c.ClearFault(r.Context(), id)
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, fmt.Sprintf("no active fault %q", id))
+		return
+	}
+	if err != nil {
+		// Cleared concurrently, by recovery or another request
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "cleared",
+		"id":     fault.ID,
+		"type":   fault.Type,
+		"node":   fault.Node,
+	})
+}
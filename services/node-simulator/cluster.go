@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math"
 	"math/rand"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -17,15 +20,27 @@ type GPUModel string
 const (
 	GPUModelA100 GPUModel = "NVIDIA-A100-80GB"
 	GPUModelH100 GPUModel = "NVIDIA-H100-80GB"
+	GPUModelV100 GPUModel = "NVIDIA-V100-32GB"
+	GPUModelL40S GPUModel = "NVIDIA-L40S-48GB"
 )
 
+// gpuModelNames maps short config names to GPU models
+var gpuModelNames = map[string]GPUModel{
+	"a100": GPUModelA100,
+	"h100": GPUModelH100,
+	"v100": GPUModelV100,
+	"l40s": GPUModelL40S,
+}
+
 // GPUSpec holds GPU specifications
 type GPUSpec struct {
-	Model       GPUModel
-	MemoryMiB   float64
-	MaxPowerW   float64
-	MaxTempC    float64
-	BaseSMClock float64
+	Model        GPUModel
+	MemoryMiB    float64
+	IdlePowerW   float64 // Draw at 0% utilization
+	MaxPowerW    float64
+	MaxTempC     float64
+	BaseSMClock  float64
+	BoostSMClock float64 // Reached under load with ample thermal headroom
 	BaseMemClock float64
 }
 
@@ -33,19 +48,172 @@ var gpuSpecs = map[GPUModel]GPUSpec{
 	GPUModelA100: {
 		Model:        GPUModelA100,
 		MemoryMiB:    81920, // 80GB
+		IdlePowerW:   55,
 		MaxPowerW:    400,
 		MaxTempC:     83,
 		BaseSMClock:  1410,
+		BoostSMClock: 1545,
 		BaseMemClock: 1593,
 	},
 	GPUModelH100: {
 		Model:        GPUModelH100,
 		MemoryMiB:    81920, // 80GB
+		IdlePowerW:   75,
 		MaxPowerW:    700,
 		MaxTempC:     83,
 		BaseSMClock:  1980,
+		BoostSMClock: 2100,
 		BaseMemClock: 2619,
 	},
+	GPUModelV100: {
+		Model:        GPUModelV100,
+		MemoryMiB:    32768, // 32GB
+		IdlePowerW:   35,
+		MaxPowerW:    300,
+		MaxTempC:     83,
+		BaseSMClock:  1290,
+		BoostSMClock: 1530,
+		BaseMemClock: 877,
+	},
+	GPUModelL40S: {
+		Model:        GPUModelL40S,
+		MemoryMiB:    49152, // 48GB
+		IdlePowerW:   35,
+		MaxPowerW:    350,
+		MaxTempC:     87,
+		BaseSMClock:  1110,
+		BoostSMClock: 2520,
+		BaseMemClock: 9001,
+	},
+}
+
+// modelWeight is one entry of a weighted GPU model distribution
+type modelWeight struct {
+	model  GPUModel
+	weight float64
+}
+
+// parseModelWeights parses a "model=weight,..." spec such as
+// "a100=60,h100=30,v100=10". Weights are relative and must be non-negative
+// with a positive sum.
+func parseModelWeights(spec string) ([]modelWeight, error) {
+	var weights []modelWeight
+	total := 0.0
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid GPU model weight %q", entry)
+		}
+		model, known := gpuModelNames[strings.ToLower(strings.TrimSpace(name))]
+		if !known {
+			return nil, fmt.Errorf("unknown GPU model %q in weights", name)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return nil, fmt.Errorf("invalid weight %q for GPU model %q", value, name)
+		}
+		weights = append(weights, modelWeight{model: model, weight: weight})
+		total += weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("GPU model weights must sum to a positive value")
+	}
+	return weights, nil
+}
+
+// parseModelCounts parses a "model:count,..." spec such as
+// "A100:2,H100:4,L40S:2" into the sequence of GPU node models it describes,
+// in order. Entries with an unknown model or a bad count are warned about
+// and skipped.
+func parseModelCounts(spec string) []GPUModel {
+	var models []GPUModel
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, ":")
+		model, known := gpuModelNames[strings.ToLower(strings.TrimSpace(name))]
+		if !known {
+			slog.Warn("Skipping unknown GPU model in GPU_MODELS", "entry", entry)
+			continue
+		}
+		count := 1
+		if ok {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				slog.Warn("Skipping invalid GPU model count in GPU_MODELS", "entry", entry)
+				continue
+			}
+			count = n
+		}
+		for i := 0; i < count; i++ {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// pickModel draws a GPU model from the weighted distribution
+func pickModel(rng *rand.Rand, weights []modelWeight) GPUModel {
+	total := 0.0
+	for _, w := range weights {
+		total += w.weight
+	}
+	target := rng.Float64() * total
+	for _, w := range weights {
+		if target < w.weight {
+			return w.model
+		}
+		target -= w.weight
+	}
+	return weights[len(weights)-1].model
+}
+
+// parseNodeModels parses a "node=model,...;node=model,..." spec listing the
+// per-GPU models of mixed nodes, such as "gpu-node-02=a100*4,h100*4". A
+// "*N" suffix repeats a model N times.
+func parseNodeModels(spec string) (map[string][]GPUModel, error) {
+	nodeModels := make(map[string][]GPUModel)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		nodeID, list, ok := strings.Cut(entry, "=")
+		nodeID = strings.TrimSpace(nodeID)
+		if !ok || nodeID == "" {
+			return nil, fmt.Errorf("invalid GPU node models %q", entry)
+		}
+		if _, dup := nodeModels[nodeID]; dup {
+			return nil, fmt.Errorf("GPU models for node %q given twice", nodeID)
+		}
+		var models []GPUModel
+		for _, item := range strings.Split(list, ",") {
+			name, repeat, hasRepeat := strings.Cut(strings.TrimSpace(item), "*")
+			model, known := gpuModelNames[strings.ToLower(strings.TrimSpace(name))]
+			if !known {
+				return nil, fmt.Errorf("unknown GPU model %q for node %q", name, nodeID)
+			}
+			count := 1
+			if hasRepeat {
+				n, err := strconv.Atoi(strings.TrimSpace(repeat))
+				if err != nil || n < 1 {
+					return nil, fmt.Errorf("invalid repeat %q for node %q", repeat, nodeID)
+				}
+				count = n
+			}
+			for i := 0; i < count; i++ {
+				models = append(models, model)
+			}
+		}
+		nodeModels[nodeID] = models
+	}
+	return nodeModels, nil
 }
 
 // GPU represents a single GPU
@@ -54,14 +222,27 @@ type GPU struct {
 	Model       GPUModel
 	Spec        GPUSpec
 	Utilization float64
+	Bandwidth   float64 // Memory bandwidth utilization percentage
+	Workload    WorkloadProfile
 	MemUsed     float64
 	Temperature float64
+	TempRate    float64 // Smoothed Celsius per minute, from tick-to-tick deltas
 	PowerUsage  float64
+	PowerLimit  float64 // Enforced cap in watts; the model's max draw unless capped
 	SMClock     float64
 	MemClock    float64
 	ECCErrors   float64
 	PCIeTx      float64
 	PCIeRx      float64
+	UtilFloor   float64    // Reserved GPUs never report utilization below this
+	FloorSince  time.Time  // When the current floor was set
+	Ejected     bool       // Fell off the bus, not reporting metrics
+	EjectedAt   time.Time  // Zero when not ejected
+	XIDCode     int        // XID reported when ejected
+	RecoverAt   time.Time  // Zero means no automatic recovery
+	indexLabel  string     // Index formatted once for metric labels; it never changes
+	series      *gpuSeries // Cached metric handles; nil once its series are deleted
+	log         *slog.Logger
 }
 
 // Node represents a compute node
@@ -72,40 +253,180 @@ type Node struct {
 	CPUUtilization float64
 	MemoryUsed     float64
 	MemoryTotal    float64
+	MemoryPressure float64 // Target memory utilization % under a pressure fault; 0 when none
+	PressureSince  time.Time
+	OnlineAt       time.Time // Held down until then by the startup stagger; zero once up
 	NetworkRx      float64
 	NetworkTx      float64
+	EnergyJoules   float64
+	IBPorts        []*IBPort // Nil unless INFINIBAND_ENABLED
 	IsUp           bool
+	Schedulable    bool // False when cordoned: running work continues, no new placements
+	cpuUtilAvg     *rollingWindow
+	gpuUtilAvg     *rollingWindow
+	log            *slog.Logger // Carries node and node_type on every line
 	mu             sync.RWMutex
 }
 
+// IsGPUNode reports whether the node carries simulated GPUs. It is the one
+// check for GPU presence: only GPU nodes have GPU state such as gpuUtilAvg.
+func (n *Node) IsGPUNode() bool {
+	return n.Type == "gpu"
+}
+
+// rollingWindow keeps the average of the most recent values
+type rollingWindow struct {
+	values []float64
+	next   int
+	count  int
+	sum    float64
+}
+
+func newRollingWindow(size int) *rollingWindow {
+	if size < 1 {
+		size = 1
+	}
+	return &rollingWindow{values: make([]float64, size)}
+}
+
+// Add records a value, evicting the oldest once the window is full
+func (r *rollingWindow) Add(v float64) {
+	if r.count == len(r.values) {
+		r.sum -= r.values[r.next]
+	} else {
+		r.count++
+	}
+	r.values[r.next] = v
+	r.sum += v
+	r.next = (r.next + 1) % len(r.values)
+}
+
+// Average returns the mean of the values in the window, or 0 if empty
+func (r *rollingWindow) Average() float64 {
+	if r.count == 0 {
+		return 0
+	}
+	return r.sum / float64(r.count)
+}
+
 // Cluster manages all nodes
 type Cluster struct {
 	Nodes  []*Node
 	config Config
+	rng    *rand.Rand // Seeded generator for reproducible construction
+	replay *Replay    // Recorded GPU traces, nil unless REPLAY_FILE is set
 	mu     sync.RWMutex
 }
 
-// NewCluster creates a new cluster with simulated nodes
-func NewCluster(config Config) *Cluster {
+// NewCluster creates a new cluster with simulated nodes. It fails if two
+// nodes would share an ID, since their metric series would silently merge.
+func NewCluster(config Config) (*Cluster, error) {
+	seed := config.RandomSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
 	cluster := &Cluster{
 		Nodes:  make([]*Node, 0),
 		config: config,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+
+	if err := validateNodePrefix("GPU_NODE_PREFIX", config.GPUNodePrefix); err != nil {
+		return nil, err
+	}
+	if err := validateNodePrefix("CPU_NODE_PREFIX", config.CPUNodePrefix); err != nil {
+		return nil, err
+	}
+
+	var weights []modelWeight
+	if config.GPUModelWeights != "" {
+		var err error
+		if weights, err = parseModelWeights(config.GPUModelWeights); err != nil {
+			return nil, err
+		}
 	}
 
-	// Create GPU nodes
+	var modelSequence []GPUModel
+	if config.GPUModels != "" {
+		if weights != nil {
+			return nil, fmt.Errorf("GPU_MODELS and GPU_MODEL_WEIGHTS can't both be set")
+		}
+		if modelSequence = parseModelCounts(config.GPUModels); modelSequence == nil {
+			slog.Warn("GPU_MODELS has no usable entries, alternating A100/H100")
+		}
+	}
+
+	var nodeModels map[string][]GPUModel
+	if config.GPUNodeModels != "" {
+		var err error
+		if nodeModels, err = parseNodeModels(config.GPUNodeModels); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create GPU nodes, alternating A100/H100 unless counts or weights are
+	// configured. Counts are assigned in order, repeating if there are more
+	// nodes than they cover. Mixed nodes still draw a model so the rest of
+	// the cluster is unchanged.
 	for i := 0; i < config.GPUNodes; i++ {
 		model := GPUModelA100
-		if i%2 == 1 {
+		if modelSequence != nil {
+			model = modelSequence[i%len(modelSequence)]
+		} else if weights != nil {
+			model = pickModel(cluster.rng, weights)
+		} else if i%2 == 1 {
 			model = GPUModelH100
 		}
-		node := cluster.createGPUNode(fmt.Sprintf("gpu-node-%02d", i+1), model, 8)
-		cluster.Nodes = append(cluster.Nodes, node)
+		id := nodeName(config.GPUNodePrefix, i+1, config.GPUNodes, config.NodeNameWidth)
+		node, err := cluster.createGPUNode(id, model, 8, nodeModels[id])
+		if err != nil {
+			return nil, err
+		}
+		delete(nodeModels, id)
+		if err := cluster.addNode(node); err != nil {
+			return nil, err
+		}
+	}
+	for id := range nodeModels {
+		return nil, fmt.Errorf("GPU models given for unknown GPU node %q", id)
 	}
 
 	// Create CPU nodes
 	for i := 0; i < config.CPUNodes; i++ {
-		node := cluster.createCPUNode(fmt.Sprintf("cpu-node-%02d", i+1))
-		cluster.Nodes = append(cluster.Nodes, node)
+		node := cluster.createCPUNode(nodeName(config.CPUNodePrefix, i+1, config.CPUNodes, config.NodeNameWidth))
+		if err := cluster.addNode(node); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reserved GPUs keep a minimum utilization
+	if config.GPUUtilFloors != "" {
+		rules, err := parseUtilFloors(config.GPUUtilFloors)
+		if err != nil {
+			return nil, err
+		}
+		reserved := applyUtilFloors(cluster.Nodes, rules)
+		slog.Info("GPU utilization floors applied", "rules", len(rules), "gpus", reserved)
+	}
+
+	// Nodes come up over the stagger window instead of all at once
+	applyStartupStagger(cluster.Nodes, time.Duration(config.StartupStaggerSeconds)*time.Second, cluster.rng)
+
+	// Recorded traces replace generated GPU readings
+	if config.ReplayFile != "" {
+		replay, err := loadReplay(config.ReplayFile, config.ReplayAtEnd)
+		if err != nil {
+			return nil, err
+		}
+		if err := replay.validate(cluster.Nodes); err != nil {
+			return nil, err
+		}
+		cluster.replay = replay
+		slog.Info("Replaying recorded GPU traces",
+			"path", config.ReplayFile,
+			"frames", len(replay.frames),
+			"at_end", config.ReplayAtEnd,
+		)
 	}
 
 	// Set cluster-level metrics
@@ -114,37 +435,68 @@ func NewCluster(config Config) *Cluster {
 	clusterGPUsTotal.Set(float64(totalGPUs))
 
 	slog.Info("Cluster initialized",
+		"seed", seed,
 		"total_nodes", len(cluster.Nodes),
 		"gpu_nodes", config.GPUNodes,
 		"cpu_nodes", config.CPUNodes,
 		"total_gpus", totalGPUs,
 	)
 
-	return cluster
+	return cluster, nil
+}
+
+// addNode appends a node to the cluster, rejecting duplicate IDs
+func (c *Cluster) addNode(node *Node) error {
+	if c.findNode(node.ID) != nil {
+		return fmt.Errorf("duplicate node ID %q in cluster topology", node.ID)
+	}
+	c.Nodes = append(c.Nodes, node)
+	return nil
 }
 
-func (c *Cluster) createGPUNode(id string, model GPUModel, gpuCount int) *Node {
-	spec := gpuSpecs[model]
+// createGPUNode builds a node whose GPUs are all model, unless models lists
+// one model per GPU for a node mixing generations
+func (c *Cluster) createGPUNode(id string, model GPUModel, gpuCount int, models []GPUModel) (*Node, error) {
+	if models != nil && len(models) != gpuCount {
+		return nil, fmt.Errorf("node %q has %d GPUs but %d GPU models were given", id, gpuCount, len(models))
+	}
+
 	node := &Node{
 		ID:          id,
 		Type:        "gpu",
 		GPUs:        make([]*GPU, gpuCount),
 		MemoryTotal: 2048 * 1024 * 1024 * 1024, // 2TB RAM
+		IBPorts:     c.newIBPorts(),
 		IsUp:        true,
+		Schedulable: true,
+		cpuUtilAvg:  newRollingWindow(c.config.UtilAvgWindow),
+		gpuUtilAvg:  newRollingWindow(c.config.UtilAvgWindow),
+		log:         nodeLogger(id, "gpu"),
 	}
 
 	for i := 0; i < gpuCount; i++ {
+		gpuModel := model
+		if models != nil {
+			gpuModel = models[i]
+		}
+		spec := gpuSpecs[gpuModel]
+		index := strconv.Itoa(i)
 		node.GPUs[i] = &GPU{
 			Index:       i,
-			Model:       model,
+			Model:       gpuModel,
 			Spec:        spec,
-			Temperature: 35 + rand.Float64()*5, // Start at idle temp
+			Temperature: 35 + c.rng.Float64()*5, // Start at idle temp
+			Workload:    randomWorkloadProfile(c.rng),
+			PowerLimit:  spec.MaxPowerW,
 			SMClock:     spec.BaseSMClock,
 			MemClock:    spec.BaseMemClock,
+			indexLabel:  index,
+			series:      newGPUSeries(id, index, string(gpuModel)),
+			log:         node.log.With("gpu_index", index, "gpu_model", string(gpuModel)),
 		}
 	}
 
-	return node
+	return node, nil
 }
 
 func (c *Cluster) createCPUNode(id string) *Node {
@@ -153,17 +505,33 @@ func (c *Cluster) createCPUNode(id string) *Node {
 		Type:        "cpu",
 		GPUs:        nil,
 		MemoryTotal: 512 * 1024 * 1024 * 1024, // 512GB RAM
+		IBPorts:     c.newIBPorts(),
 		IsUp:        true,
+		Schedulable: true,
+		cpuUtilAvg:  newRollingWindow(c.config.UtilAvgWindow),
+		log:         nodeLogger(id, "cpu"),
 	}
 }
 
+// nodeLogger returns a logger that tags every line with the node's identity
+func nodeLogger(id, nodeType string) *slog.Logger {
+	return slog.With("node", id, "node_type", nodeType)
+}
+
 // Run starts the simulation loop
 func (c *Cluster) Run() {
-	ticker := time.NewTicker(time.Second)
+	interval := c.tickInterval()
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for range ticker.C {
 		c.simulateTick()
+
+		// Pick up tick interval changes made through /api/config
+		if next := c.tickInterval(); next != interval {
+			interval = next
+			ticker.Reset(interval)
+		}
 	}
 }
 
@@ -171,9 +539,18 @@ func (c *Cluster) simulateTick() {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	var frame replayFrame
+	if c.replay != nil {
+		frame = c.replay.Advance()
+	}
+
+	now := time.Now()
 	for _, node := range c.Nodes {
 		node.mu.Lock()
 
+		bringOnline(node, now)
+		nodeSchedulable.WithLabelValues(node.ID, node.Type).Set(boolToFloat(node.Schedulable))
+
 		if !node.IsUp {
 			// Node is down - set metrics accordingly
 			nodeUp.WithLabelValues(node.ID, node.Type).Set(0)
@@ -184,95 +561,226 @@ func (c *Cluster) simulateTick() {
 		nodeUp.WithLabelValues(node.ID, node.Type).Set(1)
 
 		// Simulate CPU utilization with some variance
-		baseLoad := 20.0 + rand.Float64()*30 // 20-50% base load
+		baseLoad := c.config.CPUBaseLoad + rand.Float64()*30 // 20-50% base load by default
 		node.CPUUtilization = clamp(baseLoad+rand.NormFloat64()*10, 0, 100)
 		cpuUtilization.WithLabelValues(node.ID, node.Type).Set(node.CPUUtilization)
+		node.cpuUtilAvg.Add(node.CPUUtilization)
 
-		// Simulate memory utilization
-		memUtil := 30.0 + rand.Float64()*40 // 30-70% typical
+		// Simulate memory utilization, 30-70% by default. Under a memory
+		// pressure fault it climbs toward the target instead.
+		memUtil := c.config.MemoryBaseLoad + rand.Float64()*c.config.MemoryVariance
+		if node.MemoryPressure > 0 {
+			memUtil = pressuredMemoryUtil(node, c.config.TimeAcceleration)
+		}
+		memUtil = clamp(memUtil, 0, 100)
 		node.MemoryUsed = node.MemoryTotal * (memUtil / 100)
 		memoryUtilization.WithLabelValues(node.ID, node.Type).Set(memUtil)
 		memoryUsedBytes.WithLabelValues(node.ID, node.Type).Set(node.MemoryUsed)
 		memoryTotalBytes.WithLabelValues(node.ID, node.Type).Set(node.MemoryTotal)
 
+		// Simulate GPU metrics if this is a GPU node
+		if node.IsGPUNode() {
+			c.simulateGPUs(node, frame)
+			node.gpuUtilAvg.Add(averageGPUUtilization(node))
+			nodeGPUImbalance.WithLabelValues(node.ID, node.Type).Set(gpuUtilizationSpread(node))
+			recordGPUAggregates(node)
+		}
+
 		// Simulate network traffic
-		rxDelta := rand.Float64() * 100 * 1024 * 1024 // Up to 100MB/s
-		txDelta := rand.Float64() * 100 * 1024 * 1024
+		var rxDelta, txDelta float64
+		if node.IsGPUNode() {
+			// Gradient all-reduce traffic scales with how busy the GPUs are
+			perUtil := c.config.NetworkBytesPerUtil
+			util := averageGPUUtilization(node)
+			rxDelta = util*perUtil*(0.9+rand.Float64()*0.2) + rand.Float64()*5*1024*1024
+			txDelta = util*perUtil*(0.9+rand.Float64()*0.2) + rand.Float64()*5*1024*1024
+		} else {
+			rxDelta = rand.Float64() * 100 * 1024 * 1024 // Up to 100MB/s
+			txDelta = rand.Float64() * 100 * 1024 * 1024
+		}
+		rxDelta *= c.config.TimeAcceleration
+		txDelta *= c.config.TimeAcceleration
 		node.NetworkRx += rxDelta
 		node.NetworkTx += txDelta
 		networkReceiveBytes.WithLabelValues(node.ID, node.Type).Add(rxDelta)
 		networkTransmitBytes.WithLabelValues(node.ID, node.Type).Add(txDelta)
+		simulateIB(node, rxDelta, txDelta)
 
-		// Simulate GPU metrics if this is a GPU node
-		if node.Type == "gpu" {
-			c.simulateGPUs(node)
-		}
+		// Integrate power over the simulated duration of this tick
+		power := nodePowerWatts(node, c.config)
+		nodeCPUPower.WithLabelValues(node.ID, node.Type).Set(cpuPowerWatts(node, c.config))
+		nodePower.WithLabelValues(node.ID, node.Type).Set(power)
+		energy := power * c.config.TickInterval.Seconds() * c.config.TimeAcceleration
+		node.EnergyJoules += energy
+		nodeEnergyJoules.WithLabelValues(node.ID, node.Type).Add(energy)
 
 		node.mu.Unlock()
 	}
+
+	var clusterEnergy float64
+	for _, node := range c.Nodes {
+		node.mu.RLock()
+		clusterEnergy += node.EnergyJoules
+		node.mu.RUnlock()
+	}
+	clusterEnergyJoules.Set(clusterEnergy)
 }
 
-func (c *Cluster) simulateGPUs(node *Node) {
+// tempRateSmoothing weights the newest tick in the temperature rate average
+const tempRateSmoothing = 0.2
+
+// simulateGPUs updates the node's GPU readings. GPUs with a sample in the
+// replay frame take their values from it; the rest are generated.
+func (c *Cluster) simulateGPUs(node *Node, frame replayFrame) {
 	for _, gpu := range node.GPUs {
-		gpuIndex := fmt.Sprintf("%d", gpu.Index)
-		gpuModel := string(gpu.Model)
+		if recoverGPU(gpu) {
+			continue // Ejected GPUs report nothing
+		}
+
+		if gpu.series == nil { // Re-created after an eject or reset deleted them
+			gpu.series = newGPUSeries(node.ID, gpu.indexLabel, string(gpu.Model))
+		}
+		series := gpu.series
+		sample, replayed := frame[replayKey{node.ID, gpu.Index}]
 
 		// Simulate GPU utilization with realistic patterns
-		// Some GPUs are heavily loaded (training), some idle
-		if rand.Float64() < 0.7 { // 70% chance of being active
-			gpu.Utilization = clamp(60+rand.NormFloat64()*20, 0, 100)
+		// Some GPUs are heavily loaded (training), some idle; memory-bound
+		// workloads keep the SMs less busy than compute-bound ones
+		smShare := workloadShares[gpu.Workload].sm
+		if replayed {
+			gpu.Utilization = clamp(sample.Utilization, 0, 100)
+		} else if rand.Float64() < c.config.GPUActiveProbability { // 70% chance of being active by default
+			gpu.Utilization = clamp((60+rand.NormFloat64()*20)*smShare, 0, 100)
 		} else {
-			gpu.Utilization = clamp(rand.Float64()*20, 0, 100) // Idle
+			gpu.Utilization = clamp(rand.Float64()*20*smShare, 0, 100) // Idle
 		}
-		gpuUtilization.WithLabelValues(node.ID, gpuIndex, gpuModel).Set(gpu.Utilization)
+		if !replayed {
+			gpu.Utilization = math.Max(gpu.Utilization, gpu.UtilFloor) // Reserved GPUs keep a baseline load
+		}
+		series.utilization.Set(gpu.Utilization)
+
+		// Memory bandwidth follows the same activity, weighted by profile
+		gpu.Bandwidth = memBandwidthFor(gpu.Workload, gpu.Utilization)
+		series.memBandwidth.Set(gpu.Bandwidth)
 
 		// Memory utilization correlates with GPU utilization
 		memUtil := gpu.Utilization * 0.8 + rand.Float64()*20
+		if replayed && sample.MemoryUsedMiB != nil {
+			memUtil = *sample.MemoryUsedMiB / gpu.Spec.MemoryMiB * 100
+		}
 		gpu.MemUsed = gpu.Spec.MemoryMiB * clamp(memUtil, 0, 100) / 100
-		gpuMemoryUtilization.WithLabelValues(node.ID, gpuIndex, gpuModel).Set(memUtil)
-		gpuMemoryUsed.WithLabelValues(node.ID, gpuIndex, gpuModel).Set(gpu.MemUsed)
-		gpuMemoryTotal.WithLabelValues(node.ID, gpuIndex, gpuModel).Set(gpu.Spec.MemoryMiB)
+		series.memUtilization.Set(memUtil)
+		series.memUsed.Set(gpu.MemUsed)
+		series.memTotal.Set(gpu.Spec.MemoryMiB)
 
 		// Temperature increases with utilization
+		previousTemp := gpu.Temperature
 		targetTemp := 35 + (gpu.Utilization/100)*45 // 35C idle, up to 80C at full load
-		gpu.Temperature = gpu.Temperature*0.9 + targetTemp*0.1 // Smooth transition
+		smoothing := math.Min(0.1*c.config.TimeAcceleration, 1)
+		gpu.Temperature = gpu.Temperature*(1-smoothing) + targetTemp*smoothing // Smooth transition
 		if gpu.Temperature > gpu.Spec.MaxTempC {
 			gpu.Temperature = gpu.Spec.MaxTempC // Throttle kicks in
 		}
-		gpuTemperature.WithLabelValues(node.ID, gpuIndex, gpuModel).Set(gpu.Temperature)
-
-		// Power usage correlates with utilization
-		gpu.PowerUsage = gpu.Spec.MaxPowerW * (0.1 + 0.9*(gpu.Utilization/100))
-		gpuPowerUsage.WithLabelValues(node.ID, gpuIndex, gpuModel).Set(gpu.PowerUsage)
+		if replayed && sample.Temperature != nil {
+			gpu.Temperature = *sample.Temperature // Recorded values are kept as-is
+		}
+		series.temperature.Set(gpu.Temperature)
+
+		// Rate of change per wall-clock minute, smoothed so one noisy tick
+		// doesn't look like a thermal runaway
+		perMinute := (gpu.Temperature - previousTemp) / c.config.TickInterval.Seconds() * 60
+		gpu.TempRate = gpu.TempRate*(1-tempRateSmoothing) + perMinute*tempRateSmoothing
+		series.tempRate.Set(gpu.TempRate)
+
+		// Power usage scales with utilization from the model's idle draw,
+		// held under the enforced limit
+		gpu.PowerUsage = gpu.Spec.IdlePowerW + (gpu.Spec.MaxPowerW-gpu.Spec.IdlePowerW)*gpu.Utilization/100
+		gpu.PowerUsage = math.Min(gpu.PowerUsage, gpu.PowerLimit)
+		if replayed && sample.PowerW != nil {
+			gpu.PowerUsage = *sample.PowerW
+		}
+		series.power.Set(gpu.PowerUsage)
+		series.powerLimit.Set(gpu.PowerLimit)
 
-		// Clock speeds - may throttle at high temps
+		// Clock speeds - boost while cool under load, may throttle at high temps
+		smClock := gpu.Spec.BaseSMClock + (gpu.Spec.BoostSMClock-gpu.Spec.BaseSMClock)*boostFraction(gpu)
 		throttleFactor := 1.0
 		if gpu.Temperature > 80 {
 			throttleFactor = 0.9 // 10% throttle
 		}
-		gpu.SMClock = gpu.Spec.BaseSMClock * throttleFactor
+		gpu.SMClock = smClock * throttleFactor
 		gpu.MemClock = gpu.Spec.BaseMemClock * throttleFactor
-		gpuSMClock.WithLabelValues(node.ID, gpuIndex, gpuModel).Set(gpu.SMClock)
-		gpuMemoryClock.WithLabelValues(node.ID, gpuIndex, gpuModel).Set(gpu.MemClock)
+		series.smClock.Set(gpu.SMClock)
+		series.memClock.Set(gpu.MemClock)
 
 		// Rare ECC errors
-		if rand.Float64() < 0.001 { // 0.1% chance per tick
+		if rand.Float64() < 0.001*c.config.TimeAcceleration { // 0.1% chance per tick in real time
 			gpu.ECCErrors++
-			gpuECCErrors.WithLabelValues(node.ID, gpuIndex, gpuModel).Add(1)
-			slog.Warn("ECC error detected",
-				"node", node.ID,
-				"gpu", gpuIndex,
-				"total_errors", gpu.ECCErrors,
-			)
+			series.eccErrors.Add(1)
+			gpu.log.Warn("ECC error detected", "total_errors", gpu.ECCErrors)
 		}
 
 		// PCIe traffic
-		pcieDelta := gpu.Utilization * 1024 * 1024 // Scale with utilization
+		pcieDelta := gpu.Utilization * 1024 * 1024 * c.config.TimeAcceleration // Scale with utilization
 		gpu.PCIeTx += pcieDelta
 		gpu.PCIeRx += pcieDelta
-		gpuPCIeTxBytes.WithLabelValues(node.ID, gpuIndex, gpuModel).Add(pcieDelta)
-		gpuPCIeRxBytes.WithLabelValues(node.ID, gpuIndex, gpuModel).Add(pcieDelta)
+		series.pcieTx.Add(pcieDelta)
+		series.pcieRx.Add(pcieDelta)
+	}
+}
+
+// GPUs boost only when busy and well below their temperature limit. The
+// boost ramps to full over the next boostRampC degrees of headroom, so it
+// settles back to base as a loaded GPU heats up.
+const (
+	boostMinUtilization = 50.0
+	boostMinHeadroomC   = 15.0
+	boostRampC          = 15.0
+)
+
+// boostFraction returns how far the GPU sits between its base (0) and boost
+// (1) SM clock. Caller must hold node.mu.
+func boostFraction(gpu *GPU) float64 {
+	if gpu.Utilization < boostMinUtilization {
+		return 0
+	}
+	headroom := gpu.Spec.MaxTempC - gpu.Temperature
+	return clamp((headroom-boostMinHeadroomC)/boostRampC, 0, 1)
+}
+
+// averageGPUUtilization returns the mean utilization of the node's reporting
+// GPUs. Caller must hold node.mu.
+func averageGPUUtilization(node *Node) float64 {
+	total, count := 0.0, 0
+	for _, gpu := range node.GPUs {
+		if gpu.Ejected {
+			continue
+		}
+		total += gpu.Utilization
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// gpuUtilizationSpread returns max minus min utilization across the node's
+// reporting GPUs, or 0 with fewer than two. Caller must hold node.mu.
+func gpuUtilizationSpread(node *Node) float64 {
+	lowest, highest, count := 100.0, 0.0, 0
+	for _, gpu := range node.GPUs {
+		if gpu.Ejected {
+			continue
+		}
+		lowest = math.Min(lowest, gpu.Utilization)
+		highest = math.Max(highest, gpu.Utilization)
+		count++
+	}
+	if count < 2 {
+		return 0
 	}
+	return highest - lowest
 }
 
 // HandleNodesAPI returns node information as JSON
@@ -280,14 +788,41 @@ func (c *Cluster) HandleNodesAPI(w http.ResponseWriter, r *http.Request) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	type GPUInfo struct {
+		Index          int     `json:"index"`
+		Model          string  `json:"model"`
+		MemoryTotalMiB float64 `json:"memory_total_mib"`
+		IdlePowerW     float64 `json:"idle_power_w"`
+		MaxPowerW      float64 `json:"max_power_w"`
+		PowerLimitW    float64 `json:"power_limit_w"`
+		Utilization    float64 `json:"utilization"`
+		MemBandwidth   float64 `json:"mem_bandwidth_utilization"`
+		Workload       string  `json:"workload_profile"`
+		UtilFloor      float64 `json:"utilization_floor,omitempty"`
+		Ejected        bool    `json:"ejected"`
+	}
+
+	type IBPortInfo struct {
+		Port int  `json:"port"`
+		Up   bool `json:"up"`
+	}
+
 	type NodeInfo struct {
-		ID             string  `json:"id"`
-		Type           string  `json:"type"`
-		IsUp           bool    `json:"is_up"`
-		CPUUtilization float64 `json:"cpu_utilization"`
-		MemoryUsedGB   float64 `json:"memory_used_gb"`
-		MemoryTotalGB  float64 `json:"memory_total_gb"`
-		GPUCount       int     `json:"gpu_count,omitempty"`
+		ID             string       `json:"id"`
+		Type           string       `json:"type"`
+		IsUp           bool         `json:"is_up"`
+		OnlineAt       *time.Time   `json:"online_at,omitempty"`
+		Schedulable    bool         `json:"schedulable"`
+		CPUUtilization float64      `json:"cpu_utilization"`
+		CPUUtilAvg     float64      `json:"cpu_util_avg"`
+		GPUUtilAvg     *float64     `json:"gpu_util_avg,omitempty"`
+		MemoryUsedGB   float64      `json:"memory_used_gb"`
+		MemoryTotalGB  float64      `json:"memory_total_gb"`
+		MemoryPressure float64      `json:"memory_pressure,omitempty"`
+		EnergyKWh      float64      `json:"energy_kwh"`
+		GPUCount       int          `json:"gpu_count,omitempty"`
+		GPUs           []GPUInfo    `json:"gpus,omitempty"`
+		IBPorts        []IBPortInfo `json:"ib_ports,omitempty"`
 	}
 
 	nodes := make([]NodeInfo, 0, len(c.Nodes))
@@ -297,12 +832,40 @@ func (c *Cluster) HandleNodesAPI(w http.ResponseWriter, r *http.Request) {
 			ID:             node.ID,
 			Type:           node.Type,
 			IsUp:           node.IsUp,
+			Schedulable:    node.Schedulable,
 			CPUUtilization: math.Round(node.CPUUtilization*100) / 100,
+			CPUUtilAvg:     math.Round(node.cpuUtilAvg.Average()*100) / 100,
 			MemoryUsedGB:   math.Round(node.MemoryUsed/1024/1024/1024*100) / 100,
 			MemoryTotalGB:  math.Round(node.MemoryTotal/1024/1024/1024*100) / 100,
+			MemoryPressure: node.MemoryPressure,
+			EnergyKWh:      math.Round(node.EnergyJoules/joulesPerKWh*1000) / 1000,
 		}
-		if node.GPUs != nil {
+		if !node.OnlineAt.IsZero() {
+			onlineAt := node.OnlineAt
+			info.OnlineAt = &onlineAt
+		}
+		if node.IsGPUNode() {
+			gpuAvg := math.Round(node.gpuUtilAvg.Average()*100) / 100
+			info.GPUUtilAvg = &gpuAvg
 			info.GPUCount = len(node.GPUs)
+			for _, gpu := range node.GPUs {
+				info.GPUs = append(info.GPUs, GPUInfo{
+					Index:          gpu.Index,
+					Model:          string(gpu.Model),
+					MemoryTotalMiB: gpu.Spec.MemoryMiB,
+					IdlePowerW:     gpu.Spec.IdlePowerW,
+					MaxPowerW:      gpu.Spec.MaxPowerW,
+					PowerLimitW:    gpu.PowerLimit,
+					Utilization:    math.Round(gpu.Utilization*100) / 100,
+					MemBandwidth:   math.Round(gpu.Bandwidth*100) / 100,
+					Workload:       string(gpu.Workload),
+					UtilFloor:      gpu.UtilFloor,
+					Ejected:        gpu.Ejected,
+				})
+			}
+		}
+		for _, port := range node.IBPorts {
+			info.IBPorts = append(info.IBPorts, IBPortInfo{Port: port.Number, Up: port.Up})
 		}
 		node.mu.RUnlock()
 		nodes = append(nodes, info)
@@ -315,6 +878,103 @@ func (c *Cluster) HandleNodesAPI(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// SetSchedulable cordons (false) or uncordons (true) a node. Cordoning only
+// blocks new placements; the node stays up and keeps running its work.
+func (c *Cluster) SetSchedulable(ctx context.Context, nodeID string, schedulable bool) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	node.mu.Lock()
+	node.Schedulable = schedulable
+	nodeSchedulable.WithLabelValues(node.ID, node.Type).Set(boolToFloat(schedulable))
+	node.mu.Unlock()
+
+	node.log.InfoContext(ctx, "Node schedulability changed", "schedulable", schedulable)
+	return nil
+}
+
+// SetNodeUp drains (false) or resumes (true) a node. A drained node reports
+// pulse_node_up 0 and its GPU metrics stop updating until it is resumed. A
+// node still waiting out the startup stagger is held at the new state.
+func (c *Cluster) SetNodeUp(ctx context.Context, nodeID string, up bool) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	node.mu.Lock()
+	node.IsUp = up
+	node.OnlineAt = time.Time{}
+	nodeUp.WithLabelValues(node.ID, node.Type).Set(boolToFloat(up))
+	node.mu.Unlock()
+
+	if up {
+		node.log.InfoContext(ctx, "Node resumed")
+	} else {
+		node.log.WarnContext(ctx, "Node drained")
+	}
+	return nil
+}
+
+// HandleDrain handles POST /api/nodes/{id}/drain and /resume
+func (c *Cluster) HandleDrain(up bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		nodeID := r.PathValue("id")
+		if err := c.SetNodeUp(r.Context(), nodeID, up); err != nil {
+			writeJSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		status := "drained"
+		if up {
+			status = "up"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"node":   nodeID,
+			"is_up":  up,
+			"status": status,
+		})
+	}
+}
+
+// HandleCordon handles POST /api/nodes/{id}/cordon and /uncordon
+func (c *Cluster) HandleCordon(schedulable bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		nodeID := r.PathValue("id")
+		if err := c.SetSchedulable(r.Context(), nodeID, schedulable); err != nil {
+			writeJSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		status := "cordoned"
+		if schedulable {
+			status = "schedulable"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"node":        nodeID,
+			"schedulable": schedulable,
+			"status":      status,
+		})
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func clamp(value, min, max float64) float64 {
 	if value < min {
 		return min
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pulse/config"
+)
+
+// TestMain keeps the simulator's per-node logging out of test output
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// testConfig loads the simulator's defaults with env overriding them, the
+// same way main does, and a fixed seed
+func testConfig(t testing.TB, env map[string]string) Config {
+	t.Helper()
+
+	t.Setenv("RANDOM_SEED", "1")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	var cfg Config
+	if err := config.Load(&cfg); err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	return cfg
+}
+
+func TestNewClusterRejectsDuplicateNodeIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string // Empty means NewCluster must succeed
+	}{
+		{
+			name: "distinct prefixes",
+			env:  map[string]string{"GPU_NODES": "2", "CPU_NODES": "2"},
+		},
+		{
+			name:    "GPU and CPU nodes share a prefix",
+			env:     map[string]string{"GPU_NODES": "2", "CPU_NODES": "2", "GPU_NODE_PREFIX": "node-", "CPU_NODE_PREFIX": "node-"},
+			wantErr: `"node-01"`,
+		},
+		{
+			name:    "shared prefix with more CPU than GPU nodes",
+			env:     map[string]string{"GPU_NODES": "1", "CPU_NODES": "3", "GPU_NODE_PREFIX": "n", "CPU_NODE_PREFIX": "n"},
+			wantErr: `"n01"`,
+		},
+		{
+			name:    "GPU_NODE_MODELS lists a node twice",
+			env:     map[string]string{"GPU_NODES": "2", "GPU_NODE_MODELS": "gpu-node-02=a100*8;gpu-node-02=h100*8"},
+			wantErr: `"gpu-node-02"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCluster(testConfig(t, tt.env))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("NewCluster: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("NewCluster succeeded, want an error naming %s", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not name %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNodeIsGPUNode(t *testing.T) {
+	cluster, err := NewCluster(testConfig(t, map[string]string{"GPU_NODES": "1", "CPU_NODES": "2"}))
+	if err != nil {
+		t.Fatalf("NewCluster: %v", err)
+	}
+	gpuNode, cpuNode, emptyGPUsNode := cluster.Nodes[0], cluster.Nodes[1], cluster.Nodes[2]
+	emptyGPUsNode.GPUs = []*GPU{} // Non-nil but empty, as a careless refactor might leave it
+
+	tests := []struct {
+		name string
+		node *Node
+		want bool
+	}{
+		{"GPU node", gpuNode, true},
+		{"CPU node with nil GPUs", cpuNode, false},
+		{"CPU node with empty GPU slice", emptyGPUsNode, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.IsGPUNode(); got != tt.want {
+				t.Errorf("IsGPUNode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A CPU node has no GPU state to update, so simulating it as a GPU node
+	// would panic
+	cluster.simulateTick()
+}
+
+// benchmarkCluster builds a cluster of GPU nodes only, each with 8 GPUs
+func benchmarkCluster(b *testing.B, gpuNodes int) *Cluster {
+	b.Helper()
+
+	cluster, err := NewCluster(testConfig(b, map[string]string{
+		"GPU_NODES": strconv.Itoa(gpuNodes),
+		"CPU_NODES": "0",
+	}))
+	if err != nil {
+		b.Fatalf("NewCluster: %v", err)
+	}
+	return cluster
+}
+
+// BenchmarkSimulateTick times one tick over a 256-node, 2048-GPU fleet,
+// which updates every GPU's DCGM series through its cached handles
+func BenchmarkSimulateTick(b *testing.B) {
+	cluster := benchmarkCluster(b, 256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cluster.simulateTick()
+	}
+}
+
+// BenchmarkGPUSeriesUpdate compares setting a node's GPU gauges through
+// the cached handles with resolving each child by labels, as ticks did
+// before the handles were cached
+func BenchmarkGPUSeriesUpdate(b *testing.B) {
+	cluster := benchmarkCluster(b, 16)
+
+	b.Run("WithLabelValues", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				for _, node := range cluster.Nodes {
+					for _, gpu := range node.GPUs {
+						model := string(gpu.Model)
+						gpuUtilization.WithLabelValues(node.ID, gpu.indexLabel, model).Set(gpu.Utilization)
+						gpuMemoryUsed.WithLabelValues(node.ID, gpu.indexLabel, model).Set(gpu.MemUsed)
+						gpuTemperature.WithLabelValues(node.ID, gpu.indexLabel, model).Set(gpu.Temperature)
+						gpuPowerUsage.WithLabelValues(node.ID, gpu.indexLabel, model).Set(gpu.PowerUsage)
+						gpuSMClock.WithLabelValues(node.ID, gpu.indexLabel, model).Set(gpu.SMClock)
+					}
+				}
+			}
+		})
+	})
+
+	b.Run("cached handles", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				for _, node := range cluster.Nodes {
+					for _, gpu := range node.GPUs {
+						series := gpu.series
+						series.utilization.Set(gpu.Utilization)
+						series.memUsed.Set(gpu.MemUsed)
+						series.temperature.Set(gpu.Temperature)
+						series.power.Set(gpu.PowerUsage)
+						series.smClock.Set(gpu.SMClock)
+					}
+				}
+			}
+		})
+	})
+}
+
+// BenchmarkGPUIndexLabel compares formatting each GPU's index label on
+// every tick, as simulateGPUs once did with fmt.Sprintf, with the label
+// cached at creation, across a 256-node, 2048-GPU fleet
+func BenchmarkGPUIndexLabel(b *testing.B) {
+	cluster := benchmarkCluster(b, 256)
+	var label string
+
+	b.Run("formatted per tick", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, node := range cluster.Nodes {
+				for _, gpu := range node.GPUs {
+					label = fmt.Sprintf("%d", gpu.Index)
+				}
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, node := range cluster.Nodes {
+				for _, gpu := range node.GPUs {
+					label = gpu.indexLabel
+				}
+			}
+		}
+	})
+	_ = label
+}
+
+// BenchmarkSimulateTickRebuildingSeries times ticks that re-resolve every
+// GPU's metric handles, as after a mass eject or reset, which is the path
+// that uses the cached index label
+func BenchmarkSimulateTickRebuildingSeries(b *testing.B) {
+	cluster := benchmarkCluster(b, 256)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for _, node := range cluster.Nodes {
+			for _, gpu := range node.GPUs {
+				gpu.series = nil
+			}
+		}
+		b.StartTimer()
+		cluster.simulateTick()
+	}
+}
+
+// Bad entries in GPU_MODELS are warned about and skipped rather than
+// failing startup
+func TestParseModelCounts(t *testing.T) {
+	a100, h100, v100, l40s := GPUModelA100, GPUModelH100, GPUModelV100, GPUModelL40S
+	tests := []struct {
+		name string
+		spec string
+		want []GPUModel
+	}{
+		{"in order", "A100:1,h100:2,L40S:1", []GPUModel{a100, h100, h100, l40s}},
+		{"spaces and trailing comma", " v100 : 2 ,", []GPUModel{v100, v100}},
+		{"unknown model skipped", "A100:2,B200:2", []GPUModel{a100, a100}},
+		{"missing count means one", "A100:2,H100", []GPUModel{a100, a100, h100}},
+		{"zero count skipped", "A100:2,H100:0", []GPUModel{a100, a100}},
+		{"bad count skipped", "A100:two,L40S:1", []GPUModel{l40s}},
+		{"nothing usable", "B200:2,A100:-1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseModelCounts(tt.spec); !slices.Equal(got, tt.want) {
+				t.Errorf("parseModelCounts(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+// A GPU_MODELS sequence shorter than GPU_NODES starts over, and one with no
+// usable entries falls back to alternating A100/H100
+func TestNewClusterGPUModels(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want []GPUModel
+	}{
+		{"sequence repeats", "L40S:1,V100:2", []GPUModel{GPUModelL40S, GPUModelV100, GPUModelV100, GPUModelL40S, GPUModelV100}},
+		{"sequence cut off", "H100:3,V100:4", []GPUModel{GPUModelH100, GPUModelH100, GPUModelH100, GPUModelV100, GPUModelV100}},
+		{"no usable entries", "B200:3", []GPUModel{GPUModelA100, GPUModelH100, GPUModelA100, GPUModelH100, GPUModelA100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster, err := NewCluster(testConfig(t, map[string]string{
+				"GPU_NODES":  "5",
+				"CPU_NODES":  "0",
+				"GPU_MODELS": tt.spec,
+			}))
+			if err != nil {
+				t.Fatalf("NewCluster: %v", err)
+			}
+			got := make([]GPUModel, len(cluster.Nodes))
+			for i, node := range cluster.Nodes {
+				got[i] = node.GPUs[0].Model
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("node models %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
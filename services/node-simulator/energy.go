@@ -0,0 +1,30 @@
+package main
+
+// joulesPerKWh converts integrated energy to the kWh shown in /api/nodes
+const joulesPerKWh = 3.6e6
+
+// Host power model for energy accounting: a fixed base draw for the board,
+// fans, memory and disks, plus a CPU share that scales linearly with CPU
+// utilization up to the TDP configured for the node type. GPU draw comes
+// from the simulated dcgm_power_usage.
+
+// cpuPowerWatts estimates a node's CPU package draw. Caller must hold node.mu.
+func cpuPowerWatts(node *Node, cfg Config) float64 {
+	tdp := cfg.CPUNodeCPUTDPWatts
+	if node.IsGPUNode() {
+		tdp = cfg.GPUNodeCPUTDPWatts
+	}
+	return tdp * node.CPUUtilization / 100
+}
+
+// nodePowerWatts estimates a node's current draw from its host base draw,
+// CPU and GPUs. Ejected GPUs draw nothing. Caller must hold node.mu.
+func nodePowerWatts(node *Node, cfg Config) float64 {
+	power := cfg.HostBasePowerWatts + cpuPowerWatts(node, cfg)
+	for _, gpu := range node.GPUs {
+		if !gpu.Ejected {
+			power += gpu.PowerUsage
+		}
+	}
+	return power
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// XID codes reported by the NVIDIA driver
+const (
+	XIDGPUFellOffBus = 79
+)
+
+// GPUEjectRequest is the payload for the GPU ejection fault
+type GPUEjectRequest struct {
+	Node                string `json:"node"`
+	GPUIndex            int    `json:"gpu_index"`
+	XID                 int    `json:"xid"`
+	RecoverAfterSeconds int    `json:"recover_after_seconds"`
+}
+
+// findNode returns the node with the given ID, or nil. Caller must hold c.mu.
+func (c *Cluster) findNode(id string) *Node {
+	for _, node := range c.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// EjectGPU simulates a GPU falling off the PCIe bus. The GPU stops reporting
+// metrics and an XID error is recorded. If recoverAfter is non-zero the GPU
+// comes back on the first tick after that interval.
+func (c *Cluster) EjectGPU(ctx context.Context, nodeID string, gpuIndex, xid int, recoverAfter time.Duration) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	if gpuIndex < 0 || gpuIndex >= len(node.GPUs) {
+		return fmt.Errorf("gpu index %d out of range for node %q", gpuIndex, nodeID)
+	}
+	gpu := node.GPUs[gpuIndex]
+	if gpu.Ejected {
+		return fmt.Errorf("gpu %d on node %q is already ejected", gpuIndex, nodeID)
+	}
+
+	now := time.Now()
+	gpu.Ejected = true
+	gpu.EjectedAt = now
+	gpu.XIDCode = xid
+	gpu.RecoverAt = time.Time{}
+	if recoverAfter > 0 {
+		gpu.RecoverAt = now.Add(recoverAfter)
+	}
+
+	gpuModel := string(gpu.Model)
+	deleteGPUSeries(node.ID, gpu.indexLabel, gpuModel)
+	gpu.series = nil
+	gpuXIDErrors.WithLabelValues(node.ID, gpu.indexLabel, gpuModel, fmt.Sprintf("%d", xid)).Inc()
+
+	gpu.log.WarnContext(ctx, "GPU fell off the bus",
+		"xid", xid,
+		"recover_after", recoverAfter,
+	)
+	return nil
+}
+
+// recoverGPU brings an ejected GPU back once its reset interval has elapsed.
+// Caller must hold the owning node.mu. Returns true if the GPU is still ejected.
+func recoverGPU(gpu *GPU) bool {
+	if !gpu.Ejected {
+		return false
+	}
+	if gpu.RecoverAt.IsZero() || time.Now().Before(gpu.RecoverAt) {
+		return true
+	}
+
+	restoreGPU(gpu)
+	gpu.log.Info("GPU recovered after reset")
+	return false
+}
+
+// restoreGPU puts an ejected GPU back on the bus. Its series are re-created
+// on the next tick. Caller must hold the owning node.mu.
+func restoreGPU(gpu *GPU) {
+	gpu.Ejected = false
+	gpu.EjectedAt = time.Time{}
+	gpu.XIDCode = 0
+	gpu.RecoverAt = time.Time{}
+	gpu.Temperature = 35 + rand.Float64()*5 // Back at idle temp after reset
+}
+
+// RecoverGPU ends a GPU's ejection early, as if its reset had completed.
+// Unlike ResetGPU it leaves ECC counters and the reset count alone.
+func (c *Cluster) RecoverGPU(ctx context.Context, nodeID string, gpuIndex int) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	if gpuIndex < 0 || gpuIndex >= len(node.GPUs) {
+		return fmt.Errorf("gpu index %d out of range for node %q", gpuIndex, nodeID)
+	}
+	gpu := node.GPUs[gpuIndex]
+	if !gpu.Ejected {
+		return fmt.Errorf("gpu %d on node %q is not ejected", gpuIndex, nodeID)
+	}
+
+	restoreGPU(gpu)
+	gpu.log.InfoContext(ctx, "GPU recovered early")
+	return nil
+}
+
+// ResetGPU simulates a GPU reset: any active fault is cleared, ECC error
+// counters start over and temperature and clocks return to idle
+func (c *Cluster) ResetGPU(ctx context.Context, nodeID string, gpuIndex int) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	if gpuIndex < 0 || gpuIndex >= len(node.GPUs) {
+		return fmt.Errorf("gpu index %d out of range for node %q", gpuIndex, nodeID)
+	}
+	gpu := node.GPUs[gpuIndex]
+
+	wasEjected := gpu.Ejected
+	gpu.Ejected = false
+	gpu.EjectedAt = time.Time{}
+	gpu.XIDCode = 0
+	gpu.RecoverAt = time.Time{}
+	gpu.ECCErrors = 0
+	gpu.Utilization = 0
+	gpu.Bandwidth = 0
+	gpu.Temperature = 35 + rand.Float64()*5
+	gpu.TempRate = 0
+	gpu.SMClock = gpu.Spec.BaseSMClock
+	gpu.MemClock = gpu.Spec.BaseMemClock
+
+	gpuModel := string(gpu.Model)
+	gpuECCErrors.DeleteLabelValues(node.ID, gpu.indexLabel, gpuModel)
+	gpu.series = nil // Its ECC counter was just deleted
+	gpuResets.WithLabelValues(node.ID, gpu.indexLabel, gpuModel).Inc()
+
+	gpu.log.InfoContext(ctx, "GPU reset", "cleared_fault", wasEjected)
+	return nil
+}
+
+// HandleGPUReset handles POST /api/nodes/{id}/gpus/{index}/reset
+func (c *Cluster) HandleGPUReset(w http.ResponseWriter, r *http.Request) {
+	nodeID := r.PathValue("id")
+	gpuIndex, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "gpu index must be an integer")
+		return
+	}
+
+	if err := c.ResetGPU(r.Context(), nodeID, gpuIndex); err != nil {
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    "reset",
+		"node":      nodeID,
+		"gpu_index": gpuIndex,
+	})
+}
+
+// deleteGPUSeries removes all per-GPU series so the GPU appears absent
+func deleteGPUSeries(nodeID, gpuIndex, gpuModel string) {
+	gpuUtilization.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuMemoryUtilization.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuMemBandwidthUtilization.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuMemoryUsed.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuMemoryTotal.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuTemperature.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuTempRate.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuPowerUsage.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuPowerLimit.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuSMClock.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuMemoryClock.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuECCErrors.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuPCIeTxBytes.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+	gpuPCIeRxBytes.DeleteLabelValues(nodeID, gpuIndex, gpuModel)
+}
+
+// HandleGPUEject handles POST /api/faults/gpu-eject
+func (c *Cluster) HandleGPUEject(w http.ResponseWriter, r *http.Request) {
+	var req GPUEjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.XID == 0 {
+		req.XID = XIDGPUFellOffBus
+	}
+	if req.RecoverAfterSeconds < 0 {
+		writeJSONError(w, http.StatusBadRequest, "recover_after_seconds must be non-negative")
+		return
+	}
+
+	recoverAfter := time.Duration(req.RecoverAfterSeconds) * time.Second
+	if err := c.EjectGPU(r.Context(), req.Node, req.GPUIndex, req.XID, recoverAfter); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":                "ejected",
+		"node":                  req.Node,
+		"gpu_index":             req.GPUIndex,
+		"xid":                   req.XID,
+		"recover_after_seconds": req.RecoverAfterSeconds,
+	})
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
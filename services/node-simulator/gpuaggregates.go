@@ -0,0 +1,44 @@
+package main
+
+import "math"
+
+// gpuAggregates rolls a node's reporting GPUs up into per-node values
+type gpuAggregates struct {
+	powerSum   float64
+	utilAvg    float64
+	memUsedSum float64
+	tempMax    float64
+	reporting  int
+}
+
+// nodeGPUAggregates sums and maxes over the node's reporting GPUs; ejected
+// GPUs are left out, matching their absent dcgm_* series. Caller must hold
+// node.mu.
+func nodeGPUAggregates(node *Node) gpuAggregates {
+	var agg gpuAggregates
+	for _, gpu := range node.GPUs {
+		if gpu.Ejected {
+			continue
+		}
+		agg.powerSum += gpu.PowerUsage
+		agg.utilAvg += gpu.Utilization
+		agg.memUsedSum += gpu.MemUsed
+		agg.tempMax = math.Max(agg.tempMax, gpu.Temperature)
+		agg.reporting++
+	}
+	if agg.reporting > 0 {
+		agg.utilAvg /= float64(agg.reporting)
+	}
+	return agg
+}
+
+// recordGPUAggregates publishes the node's GPU rollup gauges. Caller must
+// hold node.mu.
+func recordGPUAggregates(node *Node) {
+	agg := nodeGPUAggregates(node)
+	nodeGPUPowerSum.WithLabelValues(node.ID, node.Type).Set(agg.powerSum)
+	nodeGPUUtilizationAvg.WithLabelValues(node.ID, node.Type).Set(agg.utilAvg)
+	nodeGPUMemoryUsedSum.WithLabelValues(node.ID, node.Type).Set(agg.memUsedSum)
+	nodeGPUTempMax.WithLabelValues(node.ID, node.Type).Set(agg.tempMax)
+	nodeGPUsReporting.WithLabelValues(node.ID, node.Type).Set(float64(agg.reporting))
+}
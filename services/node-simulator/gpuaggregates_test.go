@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Aggregates over empty or GPU-less clusters must come out as 0 (or be
+// absent), never NaN or Inf
+func TestAggregatesWithoutGPUs(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"empty cluster", map[string]string{"GPU_NODES": "0", "CPU_NODES": "0"}},
+		{"all-CPU cluster", map[string]string{"GPU_NODES": "0", "CPU_NODES": "3", "CPU_NODE_PREFIX": "allcpu-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster, err := NewCluster(testConfig(t, tt.env))
+			if err != nil {
+				t.Fatalf("NewCluster: %v", err)
+			}
+			for i := 0; i < 3; i++ {
+				cluster.simulateTick()
+			}
+
+			for _, node := range cluster.Nodes {
+				if got := averageGPUUtilization(node); got != 0 {
+					t.Errorf("%s: averageGPUUtilization = %v, want 0", node.ID, got)
+				}
+				if got := gpuUtilizationSpread(node); got != 0 {
+					t.Errorf("%s: gpuUtilizationSpread = %v, want 0", node.ID, got)
+				}
+				if agg := nodeGPUAggregates(node); agg != (gpuAggregates{}) {
+					t.Errorf("%s: nodeGPUAggregates = %+v, want zero", node.ID, agg)
+				}
+				if got := node.cpuUtilAvg.Average(); math.IsNaN(got) || math.IsInf(got, 0) {
+					t.Errorf("%s: CPU utilization average = %v", node.ID, got)
+				}
+			}
+
+			checkGatheredMetrics(t, cluster)
+
+			// NaN or Inf would make the JSON encoder fail
+			rec := httptest.NewRecorder()
+			cluster.HandleNodesAPI(rec, httptest.NewRequest(http.MethodGet, "/api/nodes", nil))
+			var body map[string]interface{}
+			if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &body) != nil {
+				t.Errorf("/api/nodes: status %d, body %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+// checkGatheredMetrics fails on any NaN or Inf sample, and on GPU rollup
+// series for the cluster's nodes, which only GPU nodes may carry. The
+// cluster-wide GPU total must be 0.
+func checkGatheredMetrics(t *testing.T, cluster *Cluster) {
+	t.Helper()
+
+	ids := make(map[string]bool)
+	for _, node := range cluster.Nodes {
+		ids[node.ID] = true
+	}
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var value float64
+			switch {
+			case metric.GetGauge() != nil:
+				value = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				value = metric.GetCounter().GetValue()
+			default:
+				continue
+			}
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				t.Errorf("%s %v = %v", family.GetName(), metric.GetLabel(), value)
+			}
+
+			if !strings.HasPrefix(family.GetName(), "pulse_node_gpu") {
+				continue
+			}
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "node" && ids[label.GetValue()] {
+					t.Errorf("%s has a series for GPU-less node %s", family.GetName(), label.GetValue())
+				}
+			}
+		}
+		if family.GetName() == "pulse_cluster_gpus_total" {
+			if got := family.GetMetric()[0].GetGauge().GetValue(); got != 0 {
+				t.Errorf("pulse_cluster_gpus_total = %v, want 0", got)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// utilFloorRule reserves the GPUs whose "node/index" matches pattern
+type utilFloorRule struct {
+	pattern string
+	floor   float64
+}
+
+// parseUtilFloors parses a "pattern=floor,..." spec such as
+// "gpu-node-01/0=30,gpu-node-0*/7=15". Patterns use path.Match syntax against
+// "node/index"; floors are utilization percentages.
+func parseUtilFloors(spec string) ([]utilFloorRule, error) {
+	var rules []utilFloorRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pattern, value, ok := strings.Cut(entry, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || !strings.Contains(pattern, "/") {
+			return nil, fmt.Errorf("invalid GPU utilization floor %q, want node/index=percent", entry)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid GPU pattern %q: %w", pattern, err)
+		}
+		floor, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || floor < 0 || floor > 100 {
+			return nil, fmt.Errorf("invalid utilization floor %q for %q, want 0-100", value, pattern)
+		}
+		rules = append(rules, utilFloorRule{pattern: pattern, floor: floor})
+	}
+	return rules, nil
+}
+
+// applyUtilFloors sets each GPU's floor from the first matching rule and
+// returns how many GPUs were reserved
+func applyUtilFloors(nodes []*Node, rules []utilFloorRule) int {
+	now := time.Now()
+	reserved := 0
+	for _, node := range nodes {
+		for _, gpu := range node.GPUs {
+			key := fmt.Sprintf("%s/%d", node.ID, gpu.Index)
+			for _, rule := range rules {
+				if matched, _ := path.Match(rule.pattern, key); matched {
+					gpu.UtilFloor = rule.floor
+					gpu.FloorSince = now
+					reserved++
+					break
+				}
+			}
+		}
+	}
+	return reserved
+}
+
+// SetGPUFloor changes a GPU's utilization floor at runtime; 0 clears it
+func (c *Cluster) SetGPUFloor(ctx context.Context, nodeID string, gpuIndex int, floor float64) error {
+	if floor < 0 || floor > 100 {
+		return fmt.Errorf("floor must be between 0 and 100")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	if gpuIndex < 0 || gpuIndex >= len(node.GPUs) {
+		return fmt.Errorf("gpu index %d out of range for node %q", gpuIndex, nodeID)
+	}
+	gpu := node.GPUs[gpuIndex]
+	switch {
+	case floor == 0:
+		gpu.FloorSince = time.Time{}
+	case gpu.UtilFloor == 0:
+		gpu.FloorSince = time.Now()
+	}
+	gpu.UtilFloor = floor
+	gpu.log.InfoContext(ctx, "GPU utilization floor set", "floor", floor)
+	return nil
+}
+
+// GPUFloorRequest is the payload for reserving a GPU
+type GPUFloorRequest struct {
+	Node     string  `json:"node"`
+	GPUIndex int     `json:"gpu_index"`
+	Floor    float64 `json:"floor"`
+}
+
+// HandleGPUFloor handles POST /api/faults/gpu-floor
+func (c *Cluster) HandleGPUFloor(w http.ResponseWriter, r *http.Request) {
+	var req GPUFloorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := c.SetGPUFloor(r.Context(), req.Node, req.GPUIndex, req.Floor); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    "floor_set",
+		"node":      req.Node,
+		"gpu_index": req.GPUIndex,
+		"floor":     req.Floor,
+	})
+}
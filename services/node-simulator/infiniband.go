@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// IBPort is a simulated InfiniBand HCA port
+type IBPort struct {
+	Number    int // 1-based, as in ibstat
+	Up        bool
+	RcvData   float64
+	XmitData  float64
+	DownSince time.Time
+	RecoverAt time.Time // Zero means the link stays down until brought up
+}
+
+// IBLinkRequest is the payload for the IB link-down and link-up faults
+type IBLinkRequest struct {
+	Node                string `json:"node"`
+	Port                int    `json:"port"`
+	RecoverAfterSeconds int    `json:"recover_after_seconds"`
+}
+
+// newIBPorts returns the node's fabric ports, or nil when InfiniBand is disabled
+func (c *Cluster) newIBPorts() []*IBPort {
+	if !c.config.InfiniBandEnabled {
+		return nil
+	}
+	ports := make([]*IBPort, c.config.IBPortsPerNode)
+	for i := range ports {
+		ports[i] = &IBPort{Number: i + 1, Up: true}
+	}
+	return ports
+}
+
+// simulateIB spreads a tick's fabric traffic across the node's active ports.
+// HPC traffic rides the fabric, so it follows the node's network deltas; a
+// downed port's share fails over to the remaining ones. Caller must hold node.mu.
+func simulateIB(node *Node, rxDelta, txDelta float64) {
+	active := 0
+	for _, port := range node.IBPorts {
+		if !port.Up && !port.RecoverAt.IsZero() && !time.Now().Before(port.RecoverAt) {
+			port.Up = true
+			port.DownSince = time.Time{}
+			port.RecoverAt = time.Time{}
+			node.log.Info("IB link recovered", "port", port.Number)
+		}
+		if port.Up {
+			active++
+		}
+	}
+
+	for _, port := range node.IBPorts {
+		portLabel := strconv.Itoa(port.Number)
+		ibPortState.WithLabelValues(node.ID, node.Type, portLabel).Set(boolToFloat(port.Up))
+		if !port.Up {
+			continue
+		}
+
+		rcv := rxDelta / float64(active) * (0.9 + rand.Float64()*0.2)
+		xmit := txDelta / float64(active) * (0.9 + rand.Float64()*0.2)
+		port.RcvData += rcv
+		port.XmitData += xmit
+		ibPortRcvData.WithLabelValues(node.ID, node.Type, portLabel).Add(rcv)
+		ibPortXmitData.WithLabelValues(node.ID, node.Type, portLabel).Add(xmit)
+	}
+}
+
+// SetIBLink takes an IB port's link down or brings it back up. When taking it
+// down with a non-zero recoverAfter the link comes back on the first tick
+// after that interval.
+func (c *Cluster) SetIBLink(ctx context.Context, nodeID string, portNumber int, up bool, recoverAfter time.Duration) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	if len(node.IBPorts) == 0 {
+		return fmt.Errorf("node %q has no InfiniBand ports", nodeID)
+	}
+	if portNumber < 1 || portNumber > len(node.IBPorts) {
+		return fmt.Errorf("port %d out of range for node %q", portNumber, nodeID)
+	}
+	port := node.IBPorts[portNumber-1]
+	port.RecoverAt = time.Time{}
+
+	if up {
+		if !port.Up {
+			port.Up = true
+			port.DownSince = time.Time{}
+			node.log.InfoContext(ctx, "IB link up", "port", portNumber)
+		}
+		return nil
+	}
+
+	if !port.Up {
+		return fmt.Errorf("port %d on node %q is already down", portNumber, nodeID)
+	}
+	now := time.Now()
+	port.Up = false
+	port.DownSince = now
+	if recoverAfter > 0 {
+		port.RecoverAt = now.Add(recoverAfter)
+	}
+
+	node.log.WarnContext(ctx, "IB link down",
+		"port", portNumber,
+		"recover_after", recoverAfter,
+	)
+	return nil
+}
+
+// HandleIBLink handles POST /api/faults/ib-link-down (up=false) and
+// /api/faults/ib-link-up (up=true)
+func (c *Cluster) HandleIBLink(up bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req IBLinkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if req.Port == 0 {
+			req.Port = 1
+		}
+		if req.RecoverAfterSeconds < 0 {
+			writeJSONError(w, http.StatusBadRequest, "recover_after_seconds must be non-negative")
+			return
+		}
+
+		recoverAfter := time.Duration(req.RecoverAfterSeconds) * time.Second
+		if err := c.SetIBLink(r.Context(), req.Node, req.Port, up, recoverAfter); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		status := "link_down"
+		if up {
+			status = "link_up"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": status,
+			"node":   req.Node,
+			"port":   req.Port,
+		})
+	}
+}
@@ -1,22 +1,28 @@
 package main
 
 import (
+	"flag"
 	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/pulse/config"
 )
 
 func main() {
+	selfTest := flag.Bool("selftest", false, "initialize, run one simulation tick and exit")
+	flag.Parse()
+
 	// Initialize structured logging
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	logger := slog.New(requestIDHandler{slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
-	}))
+	})})
 	slog.SetDefault(logger)
 
+	// Optional .env-style file, real env vars take precedence
+	config.LoadEnvFile()
+
 	// Read configuration from environment
 	config := loadConfig()
 
@@ -28,11 +34,30 @@ func main() {
 
 	// Initialize metrics
 	initMetrics()
+	initPhantomNodes(config.PhantomNodes)
 
 	// Create and start simulated nodes
-	cluster := NewCluster(config)
+	cluster, err := NewCluster(config)
+	if err != nil {
+		slog.Error("Failed to create cluster", "error", err)
+		os.Exit(1)
+	}
+
+	// Smoke-test one tick and exit without serving
+	if *selfTest || config.SelfTest {
+		os.Exit(runSelfTest(cluster))
+	}
+
+	// Profiling endpoints on a side port, off by default
+	if config.PprofEnabled {
+		startPprofServer(config.PprofPort)
+	}
+
 	go cluster.Run()
 
+	// Re-read tunable settings on SIGHUP
+	go cluster.WatchReload()
+
 	// Set up HTTP server
 	mux := http.NewServeMux()
 
@@ -43,8 +68,14 @@ func main() {
 		w.Write([]byte(`{"status":"healthy","service":"node-simulator"}`))
 	})
 
-	// Prometheus metrics endpoint
-	mux.Handle("/metrics", promhttp.Handler())
+	// Prometheus metrics endpoint (supports match[] selectors)
+	mux.Handle("/metrics", metricsHandler())
+
+	// Runtime stats, a lighter alternative to pprof during incidents
+	mux.HandleFunc("GET /api/system/stats", HandleSystemStats)
+
+	// Registered metric names, types, help and labels
+	mux.HandleFunc("GET /api/metrics/catalog", HandleMetricsCatalog)
 
 	// Cluster info endpoint
 	mux.HandleFunc("/api/nodes", func(w http.ResponseWriter, r *http.Request) {
@@ -52,9 +83,33 @@ func main() {
 		cluster.HandleNodesAPI(w, r)
 	})
 
+	// Node scheduling and up/down state
+	mux.HandleFunc("POST /api/nodes/{id}/cordon", cluster.HandleCordon(false))
+	mux.HandleFunc("POST /api/nodes/{id}/uncordon", cluster.HandleCordon(true))
+	mux.HandleFunc("POST /api/nodes/{id}/drain", cluster.HandleDrain(false))
+	mux.HandleFunc("POST /api/nodes/{id}/resume", cluster.HandleDrain(true))
+
+	// Maintenance rollout: drain, take down and restore nodes in turn
+	mux.HandleFunc("/api/cluster/rolling-upgrade", cluster.HandleRollingUpgrade)
+
+	// GPU remediation
+	mux.HandleFunc("POST /api/nodes/{id}/gpus/{index}/reset", cluster.HandleGPUReset)
+
+	// Live simulation parameters
+	mux.HandleFunc("/api/config", cluster.HandleConfigAPI)
+
+	// Fault injection endpoints
+	mux.HandleFunc("GET /api/faults", cluster.HandleListFaults)
+	mux.HandleFunc("DELETE /api/faults/{id}", cluster.HandleClearFault)
+	mux.HandleFunc("POST /api/faults/gpu-eject", cluster.HandleGPUEject)
+	mux.HandleFunc("POST /api/faults/ib-link-down", cluster.HandleIBLink(false))
+	mux.HandleFunc("POST /api/faults/ib-link-up", cluster.HandleIBLink(true))
+	mux.HandleFunc("POST /api/faults/gpu-floor", cluster.HandleGPUFloor)
+	mux.HandleFunc("POST /api/faults/memory-pressure", cluster.HandleMemoryPressure)
+
 	server := &http.Server{
 		Addr:         ":" + config.MetricsPort,
-		Handler:      mux,
+		Handler:      RequestIDMiddleware(mux),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
@@ -68,31 +123,68 @@ func main() {
 
 // Config holds application configuration
 type Config struct {
-	GPUNodes    int
-	CPUNodes    int
-	MetricsPort string
+	GPUNodes     int    `env:"GPU_NODES" default:"4" validate:"min=0,max=256"`
+	CPUNodes     int    `env:"CPU_NODES" default:"4" validate:"min=0,max=256"`
+	MetricsPort  string `env:"METRICS_PORT" default:"8080" validate:"port"`
+	PprofEnabled bool   `env:"PPROF_ENABLED" default:"false"`
+	PprofPort    string `env:"PPROF_PORT" default:"6060" validate:"port"`
+	SelfTest     bool   `env:"SELFTEST" default:"false"`
+
+	// Cluster construction; a zero seed means time-based
+	RandomSeed      int64  `env:"RANDOM_SEED" default:"0"`
+	GPUModels       string `env:"GPU_MODELS"` // Node counts per model, assigned in order
+	GPUModelWeights string `env:"GPU_MODEL_WEIGHTS"`
+	GPUNodeModels   string `env:"GPU_NODE_MODELS"` // Per-GPU models for mixed nodes
+	GPUUtilFloors   string `env:"GPU_UTIL_FLOORS"`
+
+	// Node IDs are prefix + number, padded to NodeNameWidth digits or wider
+	// if the node count needs more
+	GPUNodePrefix string `env:"GPU_NODE_PREFIX" default:"gpu-node-"`
+	CPUNodePrefix string `env:"CPU_NODE_PREFIX" default:"cpu-node-"`
+	NodeNameWidth int    `env:"NODE_NAME_WIDTH" default:"2" validate:"min=1,max=9"`
+
+	// Window over which nodes come up at start; 0 brings them all up at once
+	StartupStaggerSeconds int `env:"STARTUP_STAGGER_SECONDS" default:"0" validate:"min=0,max=3600"`
+
+	// Metric-only GPU nodes for Prometheus load testing (see phantom.go)
+	PhantomNodes int `env:"PHANTOM_NODES" default:"0" validate:"min=0,max=10000"`
+
+	// Runtime-tunable simulation parameters (see /api/config and SIGHUP)
+	GPUActiveProbability float64       `env:"GPU_ACTIVE_PROBABILITY" default:"0.7" validate:"min=0,max=1" reload:"hot"`
+	CPUBaseLoad          float64       `env:"CPU_BASE_LOAD" default:"20" validate:"min=0,max=100" reload:"hot"`
+	MemoryBaseLoad       float64       `env:"MEMORY_BASE_LOAD" default:"30" validate:"min=0,max=100" reload:"hot"`
+	MemoryVariance       float64       `env:"MEMORY_VARIANCE" default:"40" validate:"min=0,max=100" reload:"hot"`
+	TickInterval         time.Duration `env:"TICK_INTERVAL" default:"1s" validate:"min=0.1,max=60" reload:"hot"`
+
+	// Multiplier for per-tick deltas so long-term trends play out faster
+	TimeAcceleration float64 `env:"TIME_ACCELERATION" default:"1.0" validate:"min=0.01,max=1000" reload:"hot"`
+
+	// Ticks averaged for cpu_util_avg/gpu_util_avg in /api/nodes
+	UtilAvgWindow int `env:"UTIL_AVG_WINDOW" default:"10" validate:"min=1,max=3600"`
+
+	// Host power model: base draw plus CPU TDP scaled by CPU utilization. The
+	// defaults reproduce the former fixed 150-400 W host estimate.
+	HostBasePowerWatts float64 `env:"HOST_BASE_POWER_WATTS" default:"150" validate:"min=0,max=5000"`
+	GPUNodeCPUTDPWatts float64 `env:"GPU_NODE_CPU_TDP_WATTS" default:"250" validate:"min=0,max=5000"`
+	CPUNodeCPUTDPWatts float64 `env:"CPU_NODE_CPU_TDP_WATTS" default:"250" validate:"min=0,max=5000"`
+
+	// Network bytes per tick for each percent of average GPU utilization
+	NetworkBytesPerUtil float64 `env:"NETWORK_BYTES_PER_UTIL_PERCENT" default:"2097152" validate:"min=0" reload:"hot"`
+
+	// Optional InfiniBand fabric carried alongside the Ethernet model
+	InfiniBandEnabled bool `env:"INFINIBAND_ENABLED" default:"false"`
+	IBPortsPerNode    int  `env:"IB_PORTS_PER_NODE" default:"1" validate:"min=1,max=8"`
+
+	// Recorded per-GPU traces replayed one frame per tick instead of random data
+	ReplayFile  string `env:"REPLAY_FILE"`
+	ReplayAtEnd string `env:"REPLAY_AT_END" default:"loop"`
 }
 
 func loadConfig() Config {
-	return Config{
-		GPUNodes:    getEnvInt("GPU_NODES", 4),
-		CPUNodes:    getEnvInt("CPU_NODES", 4),
-		MetricsPort: getEnv("METRICS_PORT", "8080"),
-	}
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-func getEnvInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if i, err := strconv.Atoi(value); err == nil {
-			return i
-		}
+	var cfg Config
+	if err := config.Load(&cfg); err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
 	}
-	return defaultValue
+	return cfg
 }
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// memoryPressureRamp is the fraction of the gap to the target closed per
+// tick, so a node takes roughly 20 ticks to creep up like a leaking job
+const memoryPressureRamp = 0.1
+
+// pressuredMemoryUtil moves a node's memory utilization toward its pressure
+// target. Caller must hold node.mu.
+func pressuredMemoryUtil(node *Node, acceleration float64) float64 {
+	current := node.MemoryUsed / node.MemoryTotal * 100
+	step := math.Min(memoryPressureRamp*acceleration, 1)
+	return current + (node.MemoryPressure-current)*step + rand.NormFloat64()*0.3
+}
+
+// SetMemoryPressure drives a node's memory utilization toward target
+// percent; 0 returns it to the configured model
+func (c *Cluster) SetMemoryPressure(ctx context.Context, nodeID string, target float64) error {
+	if target < 0 || target > 100 {
+		return fmt.Errorf("target must be between 0 and 100")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	node := c.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node %q not found", nodeID)
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	switch {
+	case target == 0:
+		node.PressureSince = time.Time{}
+	case node.MemoryPressure == 0:
+		node.PressureSince = time.Now()
+	}
+	node.MemoryPressure = target
+	if target > 0 {
+		node.log.WarnContext(ctx, "Memory pressure applied", "target", target)
+	} else {
+		node.log.InfoContext(ctx, "Memory pressure cleared")
+	}
+	return nil
+}
+
+// MemoryPressureRequest is the payload for POST /api/faults/memory-pressure
+type MemoryPressureRequest struct {
+	Node   string  `json:"node"`
+	Target float64 `json:"target"` // Memory utilization % to climb toward; 0 clears
+}
+
+// HandleMemoryPressure handles POST /api/faults/memory-pressure
+func (c *Cluster) HandleMemoryPressure(w http.ResponseWriter, r *http.Request) {
+	var req MemoryPressureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := c.SetMemoryPressure(r.Context(), req.Node, req.Target); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status := "memory_pressure_set"
+	if req.Target == 0 {
+		status = "memory_pressure_cleared"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": status,
+		"node":   req.Node,
+		"target": req.Target,
+	})
+}
@@ -15,6 +15,14 @@ var (
 		[]string{"node", "node_type"},
 	)
 
+	nodeSchedulable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_schedulable",
+			Help: "Whether new work may be placed on the node (0 when cordoned)",
+		},
+		[]string{"node", "node_type"},
+	)
+
 	cpuUtilization = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "pulse_cpu_utilization",
@@ -63,6 +71,80 @@ var (
 		[]string{"node", "node_type"},
 	)
 
+	nodeEnergyJoules = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pulse_node_energy_joules_total",
+			Help: "Total energy consumed by the node (GPUs plus host estimate) in joules",
+		},
+		[]string{"node", "node_type"},
+	)
+
+	nodeCPUPower = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_cpu_power_watts",
+			Help: "Estimated CPU package power in Watts: the node type's CPU TDP scaled by CPU utilization",
+		},
+		[]string{"node", "node_type"},
+	)
+
+	nodePower = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_power_watts",
+			Help: "Estimated total node power in Watts: host base draw plus CPU plus GPUs",
+		},
+		[]string{"node", "node_type"},
+	)
+
+	nodeGPUImbalance = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_gpu_imbalance",
+			Help: "Spread between the busiest and idlest GPU utilization on the node in percentage points (GPU nodes only)",
+		},
+		[]string{"node", "node_type"},
+	)
+
+	// Per-node rollups of the per-GPU series, computed each tick so
+	// dashboards can scrape coarse signals without every dcgm_* series
+	nodeGPUPowerSum = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_gpu_power_sum",
+			Help: "Total power draw of the node's reporting GPUs in Watts (GPU nodes only)",
+		},
+		[]string{"node", "node_type"},
+	)
+
+	nodeGPUUtilizationAvg = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_gpu_utilization_avg",
+			Help: "Mean utilization of the node's reporting GPUs in percent (GPU nodes only)",
+		},
+		[]string{"node", "node_type"},
+	)
+
+	nodeGPUMemoryUsedSum = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_gpu_memory_used_sum",
+			Help: "Total framebuffer memory used by the node's reporting GPUs in MiB (GPU nodes only)",
+		},
+		[]string{"node", "node_type"},
+	)
+
+	nodeGPUTempMax = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_gpu_temp_max",
+			Help: "Hottest reporting GPU temperature on the node in Celsius (GPU nodes only)",
+		},
+		[]string{"node", "node_type"},
+	)
+
+	nodeGPUsReporting = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_node_gpus_reporting",
+			Help: "Number of the node's GPUs currently reporting metrics, excluding ejected ones (GPU nodes only)",
+		},
+		[]string{"node", "node_type"},
+	)
+
 	// GPU-specific metrics (DCGM-compatible naming)
 	gpuUtilization = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -80,6 +162,14 @@ var (
 		[]string{"node", "gpu_index", "gpu_model"},
 	)
 
+	gpuMemBandwidthUtilization = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dcgm_gpu_mem_bandwidth_utilization",
+			Help: "GPU memory bandwidth utilization percentage (0-100); high with low SM utilization on memory-bound workloads",
+		},
+		[]string{"node", "gpu_index", "gpu_model"},
+	)
+
 	gpuMemoryUsed = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "dcgm_memory_used",
@@ -104,6 +194,14 @@ var (
 		[]string{"node", "gpu_index", "gpu_model"},
 	)
 
+	gpuTempRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dcgm_gpu_temp_rate_celsius_per_min",
+			Help: "Smoothed rate of GPU temperature change in Celsius per minute",
+		},
+		[]string{"node", "gpu_index", "gpu_model"},
+	)
+
 	gpuPowerUsage = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "dcgm_power_usage",
@@ -112,6 +210,14 @@ var (
 		[]string{"node", "gpu_index", "gpu_model"},
 	)
 
+	gpuPowerLimit = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "dcgm_power_management_limit",
+			Help: "GPU enforced power limit in Watts; limit minus dcgm_power_usage is the power headroom",
+		},
+		[]string{"node", "gpu_index", "gpu_model"},
+	)
+
 	gpuSMClock = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "dcgm_sm_clock",
@@ -152,6 +258,47 @@ var (
 		[]string{"node", "gpu_index", "gpu_model"},
 	)
 
+	gpuXIDErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dcgm_xid_errors_total",
+			Help: "GPU XID errors reported by the driver",
+		},
+		[]string{"node", "gpu_index", "gpu_model", "xid"},
+	)
+
+	gpuResets = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dcgm_gpu_reset_total",
+			Help: "Number of GPU resets performed",
+		},
+		[]string{"node", "gpu_index", "gpu_model"},
+	)
+
+	// InfiniBand fabric metrics (INFINIBAND_ENABLED)
+	ibPortRcvData = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pulse_ib_port_rcv_data_total",
+			Help: "Total bytes received on the InfiniBand port",
+		},
+		[]string{"node", "node_type", "port"},
+	)
+
+	ibPortXmitData = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pulse_ib_port_xmit_data_total",
+			Help: "Total bytes transmitted on the InfiniBand port",
+		},
+		[]string{"node", "node_type", "port"},
+	)
+
+	ibPortState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_ib_port_state",
+			Help: "InfiniBand port link state (1 = active, 0 = down)",
+		},
+		[]string{"node", "node_type", "port"},
+	)
+
 	// Cluster-level metrics
 	clusterNodesTotal = promauto.NewGauge(
 		prometheus.GaugeOpts{
@@ -166,8 +313,53 @@ var (
 			Help: "Total number of GPUs in the cluster",
 		},
 	)
+
+	clusterEnergyJoules = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pulse_cluster_energy_joules",
+			Help: "Energy consumed by all nodes since the simulator started, in joules",
+		},
+	)
 )
 
+// gpuSeries holds one GPU's metric children, resolved once so each tick
+// sets them directly instead of hashing labels through the Vec's lock
+type gpuSeries struct {
+	utilization    prometheus.Gauge
+	memUtilization prometheus.Gauge
+	memBandwidth   prometheus.Gauge
+	memUsed        prometheus.Gauge
+	memTotal       prometheus.Gauge
+	temperature    prometheus.Gauge
+	tempRate       prometheus.Gauge
+	power          prometheus.Gauge
+	powerLimit     prometheus.Gauge
+	smClock        prometheus.Gauge
+	memClock       prometheus.Gauge
+	eccErrors      prometheus.Counter
+	pcieTx         prometheus.Counter
+	pcieRx         prometheus.Counter
+}
+
+func newGPUSeries(nodeID, gpuIndex, gpuModel string) *gpuSeries {
+	return &gpuSeries{
+		utilization:    gpuUtilization.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		memUtilization: gpuMemoryUtilization.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		memBandwidth:   gpuMemBandwidthUtilization.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		memUsed:        gpuMemoryUsed.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		memTotal:       gpuMemoryTotal.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		temperature:    gpuTemperature.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		tempRate:       gpuTempRate.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		power:          gpuPowerUsage.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		powerLimit:     gpuPowerLimit.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		smClock:        gpuSMClock.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		memClock:       gpuMemoryClock.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		eccErrors:      gpuECCErrors.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		pcieTx:         gpuPCIeTxBytes.WithLabelValues(nodeID, gpuIndex, gpuModel),
+		pcieRx:         gpuPCIeRxBytes.WithLabelValues(nodeID, gpuIndex, gpuModel),
+	}
+}
+
 func initMetrics() {
 	// Metrics are auto-registered by promauto
 	// This function can be used for any additional initialization
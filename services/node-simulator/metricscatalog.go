@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CatalogEntry describes one registered metric family
+type CatalogEntry struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Help   string   `json:"help"`
+	Labels []string `json:"labels"`
+}
+
+// metricsCatalog gathers the default registry and summarizes each family,
+// sorted by name. Vector metrics only show up once they have at least one series.
+func metricsCatalog() ([]CatalogEntry, error) {
+	families, err := prometheus.DefaultGatherer.Gather()
+
+	catalog := make([]CatalogEntry, 0, len(families))
+	for _, family := range families {
+		seen := make(map[string]bool)
+		labels := make([]string, 0)
+		for _, metric := range family.GetMetric() {
+			for _, lp := range metric.GetLabel() {
+				if !seen[lp.GetName()] {
+					seen[lp.GetName()] = true
+					labels = append(labels, lp.GetName())
+				}
+			}
+		}
+		sort.Strings(labels)
+
+		catalog = append(catalog, CatalogEntry{
+			Name:   family.GetName(),
+			Type:   strings.ToLower(family.GetType().String()),
+			Help:   family.GetHelp(),
+			Labels: labels,
+		})
+	}
+	return catalog, err
+}
+
+// HandleMetricsCatalog serves GET /api/metrics/catalog
+func HandleMetricsCatalog(w http.ResponseWriter, r *http.Request) {
+	catalog, err := metricsCatalog()
+	if err != nil {
+		// Gather returns what it could collect alongside the error
+		slog.Warn("Metrics catalog gathered with errors", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"metrics": catalog,
+		"total":   len(catalog),
+	})
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// labelMatcher matches a single label against a value, Prometheus style
+type labelMatcher struct {
+	name  string
+	op    string // "=", "!=", "=~" or "!~"
+	value string
+	re    *regexp.Regexp
+}
+
+func (m labelMatcher) matches(value string) bool {
+	switch m.op {
+	case "=":
+		return value == m.value
+	case "!=":
+		return value != m.value
+	case "=~":
+		return m.re.MatchString(value)
+	case "!~":
+		return !m.re.MatchString(value)
+	}
+	return false
+}
+
+// seriesSelector is one match[] selector: all matchers must match a series
+type seriesSelector []labelMatcher
+
+var (
+	selectorPattern = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)?\s*(?:\{(.*)\})?$`)
+	matcherPattern  = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*"((?:[^"\\]|\\.)*)"\s*$`)
+)
+
+// parseSelector parses a series selector such as
+// dcgm_gpu_temp{node="gpu-node-01",gpu_index=~"0|1"}
+func parseSelector(s string) (seriesSelector, error) {
+	parts := selectorPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if parts == nil {
+		return nil, fmt.Errorf("invalid selector %q", s)
+	}
+
+	var selector seriesSelector
+	if parts[1] != "" {
+		selector = append(selector, labelMatcher{name: "__name__", op: "=", value: parts[1]})
+	}
+
+	if body := strings.TrimSpace(parts[2]); body != "" {
+		for _, raw := range splitMatchers(body) {
+			m := matcherPattern.FindStringSubmatch(raw)
+			if m == nil {
+				return nil, fmt.Errorf("invalid label matcher %q in selector %q", raw, s)
+			}
+			matcher := labelMatcher{name: m[1], op: m[2], value: strings.ReplaceAll(m[3], `\"`, `"`)}
+			if matcher.op == "=~" || matcher.op == "!~" {
+				re, err := regexp.Compile("^(?:" + matcher.value + ")$")
+				if err != nil {
+					return nil, fmt.Errorf("invalid regex in selector %q: %w", s, err)
+				}
+				matcher.re = re
+			}
+			selector = append(selector, matcher)
+		}
+	}
+
+	if len(selector) == 0 {
+		return nil, fmt.Errorf("selector %q matches everything", s)
+	}
+	return selector, nil
+}
+
+// splitMatchers splits a matcher list on commas outside of quoted values
+func splitMatchers(body string) []string {
+	var parts []string
+	var current strings.Builder
+	inQuotes, escaped := false, false
+
+	for _, r := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ',' && !inQuotes:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if strings.TrimSpace(current.String()) != "" {
+		parts = append(parts, current.String())
+	}
+	return parts
+}
+
+func (s seriesSelector) matches(name string, metric *dto.Metric) bool {
+	for _, m := range s {
+		value := ""
+		if m.name == "__name__" {
+			value = name
+		} else {
+			for _, lp := range metric.GetLabel() {
+				if lp.GetName() == m.name {
+					value = lp.GetValue()
+					break
+				}
+			}
+		}
+		if !m.matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// filteringGatherer wraps a Gatherer and keeps only series matching at least
+// one of the selectors, like the Prometheus /federate endpoint
+type filteringGatherer struct {
+	gatherer  prometheus.Gatherer
+	selectors []seriesSelector
+}
+
+func (g filteringGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+	filtered := make([]*dto.MetricFamily, 0, len(families))
+
+	for _, family := range families {
+		var kept []*dto.Metric
+		for _, metric := range family.GetMetric() {
+			for _, selector := range g.selectors {
+				if selector.matches(family.GetName(), metric) {
+					kept = append(kept, metric)
+					break
+				}
+			}
+		}
+		if len(kept) > 0 {
+			family.Metric = kept
+			filtered = append(filtered, family)
+		}
+	}
+
+	return filtered, err
+}
+
+// metricsOpts serves OpenMetrics, with _created samples for counters, to
+// scrapers that ask for application/openmetrics-text. Everyone else keeps
+// getting the Prometheus text format.
+var metricsOpts = promhttp.HandlerOpts{
+	EnableOpenMetrics:                   true,
+	EnableOpenMetricsTextCreatedSamples: true,
+}
+
+// metricsHandler serves /metrics, filtered by any match[] selectors given
+func metricsHandler() http.Handler {
+	full := promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer, promhttp.HandlerFor(prometheus.DefaultGatherer, metricsOpts),
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		matches := r.URL.Query()["match[]"]
+		if len(matches) == 0 {
+			full.ServeHTTP(w, r)
+			return
+		}
+
+		selectors := make([]seriesSelector, 0, len(matches))
+		for _, match := range matches {
+			selector, err := parseSelector(match)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			selectors = append(selectors, selector)
+		}
+
+		gatherer := filteringGatherer{gatherer: prometheus.DefaultGatherer, selectors: selectors}
+		promhttp.HandlerFor(gatherer, metricsOpts).ServeHTTP(w, r)
+	})
+}
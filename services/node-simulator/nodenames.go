@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Prefixes end up in node IDs, which the gateway only accepts as
+// letters, digits, '-' and '_'
+var nodePrefixPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]*$`)
+
+func validateNodePrefix(name, prefix string) error {
+	if !nodePrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("%s %q may only contain letters, digits, '-' and '_'", name, prefix)
+	}
+	return nil
+}
+
+// nodeName returns the ID of the number-th node (1-based) of count. Numbers
+// are zero-padded to minWidth digits, or wider when count needs it, so IDs
+// sort in node order: 120 nodes with width 2 give dgx-001 through dgx-120.
+func nodeName(prefix string, number, count, minWidth int) string {
+	width := max(minWidth, len(strconv.Itoa(count)))
+	return fmt.Sprintf("%s%0*d", prefix, width, number)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func TestNodeNamesPastNinetyNine(t *testing.T) {
+	cluster, err := NewCluster(testConfig(t, map[string]string{
+		"GPU_NODES":       "150",
+		"CPU_NODES":       "0",
+		"GPU_NODE_PREFIX": "dgx-",
+	}))
+	if err != nil {
+		t.Fatalf("NewCluster: %v", err)
+	}
+	if len(cluster.Nodes) != 150 {
+		t.Fatalf("got %d nodes, want 150", len(cluster.Nodes))
+	}
+
+	ids := make([]string, len(cluster.Nodes))
+	seen := make(map[string]bool)
+	for i, node := range cluster.Nodes {
+		if want := fmt.Sprintf("dgx-%03d", i+1); node.ID != want {
+			t.Errorf("node %d: ID %q, want %q", i, node.ID, want)
+		}
+		if seen[node.ID] {
+			t.Errorf("node ID %q used twice", node.ID)
+		}
+		seen[node.ID] = true
+		ids[i] = node.ID
+	}
+
+	// Lexical order must match node order, so sorted listings stay numeric
+	if !sort.StringsAreSorted(ids) {
+		t.Errorf("node IDs do not sort in node order: %v", ids)
+	}
+	if ids[0] != "dgx-001" || ids[149] != "dgx-150" {
+		t.Errorf("IDs run %q to %q, want dgx-001 to dgx-150", ids[0], ids[149])
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Phantom nodes inflate the series count for Prometheus load testing. They
+// are not Cluster members: nothing is simulated per tick, and values are
+// computed from the clock at scrape time. Every series carries
+// phantom="true" so it can be dropped with {phantom!="true"}.
+
+const (
+	phantomNodePrefix   = "phantom-node-"
+	phantomGPUsPerNode  = 8
+	phantomPeriodSecond = 600 // One slow utilization cycle every 10 minutes
+)
+
+// Help text must match the real metrics, since both share a family
+var (
+	phantomNodeLabels = []string{"node", "node_type", "phantom"}
+	phantomGPULabels  = []string{"node", "gpu_index", "gpu_model", "phantom"}
+
+	phantomNodeUpDesc      = prometheus.NewDesc("pulse_node_up", "Whether the node is up (1) or down (0)", phantomNodeLabels, nil)
+	phantomCPUUtilDesc     = prometheus.NewDesc("pulse_cpu_utilization", "CPU utilization percentage (0-100)", phantomNodeLabels, nil)
+	phantomMemoryUtilDesc  = prometheus.NewDesc("pulse_memory_utilization", "Memory utilization percentage (0-100)", phantomNodeLabels, nil)
+	phantomGPUUtilDesc     = prometheus.NewDesc("dcgm_gpu_utilization", "GPU utilization percentage (0-100)", phantomGPULabels, nil)
+	phantomGPUMemUsedDesc  = prometheus.NewDesc("dcgm_memory_used", "GPU memory used in MiB", phantomGPULabels, nil)
+	phantomGPUMemTotalDesc = prometheus.NewDesc("dcgm_memory_total", "GPU total memory in MiB", phantomGPULabels, nil)
+	phantomGPUTempDesc     = prometheus.NewDesc("dcgm_gpu_temp", "GPU temperature in Celsius", phantomGPULabels, nil)
+	phantomGPUPowerDesc    = prometheus.NewDesc("dcgm_power_usage", "GPU power usage in Watts", phantomGPULabels, nil)
+)
+
+// phantomGPU is the fixed identity of one phantom GPU
+type phantomGPU struct {
+	index string
+	phase float64 // Offset into the utilization cycle so GPUs don't move in lockstep
+}
+
+type phantomNode struct {
+	id   string
+	gpus []phantomGPU
+}
+
+// phantomCollector emits the phantom series on every scrape. It is an
+// unchecked collector (Describe sends nothing) because its families are
+// shared with the real metrics, which registered them first.
+type phantomCollector struct {
+	nodes []phantomNode
+	spec  GPUSpec
+}
+
+func newPhantomCollector(count int) *phantomCollector {
+	collector := &phantomCollector{spec: gpuSpecs[GPUModelA100]}
+	for i := 0; i < count; i++ {
+		node := phantomNode{id: nodeName(phantomNodePrefix, i+1, count, 2)}
+		for j := 0; j < phantomGPUsPerNode; j++ {
+			index := strconv.Itoa(j)
+			hash := fnv.New32a()
+			hash.Write([]byte(node.id + "/" + index))
+			node.gpus = append(node.gpus, phantomGPU{index: index, phase: float64(hash.Sum32()%1000) / 1000})
+		}
+		collector.nodes = append(collector.nodes, node)
+	}
+	return collector
+}
+
+// initPhantomNodes registers count phantom GPU nodes; zero disables them
+func initPhantomNodes(count int) {
+	if count == 0 {
+		return
+	}
+	prometheus.MustRegister(newPhantomCollector(count))
+	slog.Info("Phantom nodes enabled",
+		"nodes", count,
+		"gpus", count*phantomGPUsPerNode,
+	)
+}
+
+func (p *phantomCollector) Describe(chan<- *prometheus.Desc) {}
+
+func (p *phantomCollector) Collect(ch chan<- prometheus.Metric) {
+	cycle := float64(time.Now().Unix()%phantomPeriodSecond) / phantomPeriodSecond
+	model := string(p.spec.Model)
+
+	for _, node := range p.nodes {
+		ch <- prometheus.MustNewConstMetric(phantomNodeUpDesc, prometheus.GaugeValue, 1, node.id, "gpu", "true")
+		ch <- prometheus.MustNewConstMetric(phantomCPUUtilDesc, prometheus.GaugeValue, 35, node.id, "gpu", "true")
+		ch <- prometheus.MustNewConstMetric(phantomMemoryUtilDesc, prometheus.GaugeValue, 50, node.id, "gpu", "true")
+
+		for _, gpu := range node.gpus {
+			util := 50 + 40*math.Sin(2*math.Pi*(cycle+gpu.phase))
+			labels := []string{node.id, gpu.index, model, "true"}
+			ch <- prometheus.MustNewConstMetric(phantomGPUUtilDesc, prometheus.GaugeValue, util, labels...)
+			ch <- prometheus.MustNewConstMetric(phantomGPUMemUsedDesc, prometheus.GaugeValue, p.spec.MemoryMiB*util/100, labels...)
+			ch <- prometheus.MustNewConstMetric(phantomGPUMemTotalDesc, prometheus.GaugeValue, p.spec.MemoryMiB, labels...)
+			ch <- prometheus.MustNewConstMetric(phantomGPUTempDesc, prometheus.GaugeValue, 35+util*0.4, labels...)
+			ch <- prometheus.MustNewConstMetric(phantomGPUPowerDesc, prometheus.GaugeValue, p.spec.IdlePowerW+(p.spec.MaxPowerW-p.spec.IdlePowerW)*util/100, labels...)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pulse/config"
+)
+
+// WatchReload re-reads the config file and environment on each SIGHUP and
+// applies the settings tagged reload:"hot". Other changes are logged and
+// ignored until the next restart.
+func (c *Cluster) WatchReload() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		config.LoadEnvFile()
+
+		var next Config
+		if err := config.Load(&next); err != nil {
+			slog.Error("Config reload failed, keeping current settings", "error", err)
+			continue
+		}
+		c.ReloadConfig(next)
+	}
+}
+
+// ReloadConfig applies the hot-reloadable subset of next to the running cluster
+func (c *Cluster) ReloadConfig(next Config) {
+	c.mu.Lock()
+	applied, restart, err := config.Reload(&c.config, &next)
+	current := c.config
+	c.mu.Unlock()
+
+	if err != nil {
+		slog.Error("Config reload failed", "error", err)
+		return
+	}
+	if len(restart) > 0 {
+		slog.Warn("Config changes ignored until restart", "settings", restart)
+	}
+	slog.Info("Config reloaded",
+		"applied", applied,
+		"gpu_active_probability", current.GPUActiveProbability,
+		"cpu_base_load", current.CPUBaseLoad,
+		"memory_base_load", current.MemoryBaseLoad,
+		"memory_variance", current.MemoryVariance,
+		"tick_interval", current.TickInterval,
+		"time_acceleration", current.TimeAcceleration,
+		"network_bytes_per_util_percent", current.NetworkBytesPerUtil,
+	)
+}
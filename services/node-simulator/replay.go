@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ReplaySample is one recorded per-GPU reading. Utilization is required;
+// the other values are derived from it as usual when omitted.
+type ReplaySample struct {
+	Timestamp     float64  `json:"timestamp"` // Unix seconds; orders samples into frames
+	Node          string   `json:"node"`
+	GPUIndex      int      `json:"gpu_index"`
+	Utilization   float64  `json:"utilization"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	PowerW        *float64 `json:"power_w,omitempty"`
+	MemoryUsedMiB *float64 `json:"memory_used_mib,omitempty"`
+}
+
+type replayKey struct {
+	node string
+	gpu  int
+}
+
+// replayFrame holds every sample recorded at one timestamp
+type replayFrame map[replayKey]ReplaySample
+
+// Replay steps through recorded frames, one per simulation tick
+type Replay struct {
+	path   string
+	frames []replayFrame
+	loop   bool
+	next   int
+	ended  bool
+	mu     sync.Mutex
+}
+
+// replayCSVColumns lists the CSV header; the last three columns may be blank
+var replayCSVColumns = []string{"timestamp", "node", "gpu_index", "utilization", "temperature", "power_w", "memory_used_mib"}
+
+// loadReplay reads a JSON array (.json) or CSV file of ReplaySamples
+func loadReplay(path, atEnd string) (*Replay, error) {
+	if atEnd != "loop" && atEnd != "stop" {
+		return nil, fmt.Errorf("invalid REPLAY_AT_END %q (want loop or stop)", atEnd)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var samples []ReplaySample
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&samples); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	} else if samples, err = readReplayCSV(file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("%s: no samples", path)
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Timestamp < samples[j].Timestamp })
+	var frames []replayFrame
+	for i, sample := range samples {
+		if i == 0 || sample.Timestamp != samples[i-1].Timestamp {
+			frames = append(frames, make(replayFrame))
+		}
+		frames[len(frames)-1][replayKey{sample.Node, sample.GPUIndex}] = sample
+	}
+
+	return &Replay{path: path, frames: frames, loop: atEnd == "loop"}, nil
+}
+
+func readReplayCSV(r io.Reader) ([]ReplaySample, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = len(replayCSVColumns)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	for i, column := range replayCSVColumns {
+		if strings.TrimSpace(header[i]) != column {
+			return nil, fmt.Errorf("header must be %s", strings.Join(replayCSVColumns, ","))
+		}
+	}
+
+	var samples []ReplaySample
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		sample := ReplaySample{Node: record[1]}
+		var problems []error
+		parse := func(column int, required bool) *float64 {
+			if record[column] == "" && !required {
+				return nil
+			}
+			value, err := strconv.ParseFloat(record[column], 64)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("%s %q", replayCSVColumns[column], record[column]))
+				return nil
+			}
+			return &value
+		}
+		if ts := parse(0, true); ts != nil {
+			sample.Timestamp = *ts
+		}
+		if index, err := strconv.Atoi(record[2]); err == nil {
+			sample.GPUIndex = index
+		} else {
+			problems = append(problems, fmt.Errorf("gpu_index %q", record[2]))
+		}
+		if util := parse(3, true); util != nil {
+			sample.Utilization = *util
+		}
+		sample.Temperature = parse(4, false)
+		sample.PowerW = parse(5, false)
+		sample.MemoryUsedMiB = parse(6, false)
+
+		if len(problems) > 0 {
+			return nil, fmt.Errorf("line %d: invalid %w", line, errors.Join(problems...))
+		}
+		samples = append(samples, sample)
+	}
+}
+
+// validate checks that every sample names an existing GPU
+func (r *Replay) validate(nodes []*Node) error {
+	gpus := make(map[string]int)
+	for _, node := range nodes {
+		gpus[node.ID] = len(node.GPUs)
+	}
+
+	var problems []error
+	reported := make(map[replayKey]bool)
+	for _, frame := range r.frames {
+		for key := range frame {
+			count, ok := gpus[key.node]
+			if (!ok || key.gpu < 0 || key.gpu >= count) && !reported[key] {
+				reported[key] = true
+				problems = append(problems, fmt.Errorf("replay sample for unknown GPU %s/%d", key.node, key.gpu))
+			}
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// Advance returns the frame for this tick and moves to the next. Once the
+// recording ends it wraps around when looping, otherwise holds the last frame.
+func (r *Replay) Advance() replayFrame {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next == len(r.frames) {
+		if r.loop {
+			r.next = 0
+			slog.Info("Replay restarting", "path", r.path, "frames", len(r.frames))
+		} else {
+			if !r.ended {
+				r.ended = true
+				slog.Info("Replay finished, holding last frame", "path", r.path, "frames", len(r.frames))
+			}
+			return r.frames[len(r.frames)-1]
+		}
+	}
+
+	frame := r.frames[r.next]
+	r.next++
+	return frame
+}
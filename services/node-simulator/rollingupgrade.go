@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Rolling upgrade phases, in the order each node passes through them
+const (
+	upgradePending   = "pending"
+	upgradeDraining  = "draining"  // Cordoned so no new work lands
+	upgradeUpgrading = "upgrading" // Down while the firmware "flashes"
+	upgradeDone      = "done"
+	upgradeSkipped   = "skipped" // Already down, e.g. drained by an operator
+)
+
+const maxUpgradePhaseSeconds = 3600
+
+var errUpgradeRunning = errors.New("a rolling upgrade is already running")
+
+// RollingUpgradeRequest is the payload for POST /api/cluster/rolling-upgrade
+type RollingUpgradeRequest struct {
+	Nodes        []string `json:"nodes"` // Empty means every node, in cluster order
+	DrainSeconds int      `json:"drain_seconds"`
+	NodeSeconds  int      `json:"node_seconds"` // How long each node stays down
+	Concurrency  int      `json:"concurrency"`  // Nodes upgraded at once
+}
+
+// UpgradeNodeStatus is one node's progress through a rolling upgrade
+type UpgradeNodeStatus struct {
+	Node       string     `json:"node"`
+	Phase      string     `json:"phase"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// RollingUpgrade is the progress of the current or most recent rollout
+type RollingUpgrade struct {
+	State        string               `json:"state"` // running or completed
+	DrainSeconds int                  `json:"drain_seconds"`
+	NodeSeconds  int                  `json:"node_seconds"`
+	Concurrency  int                  `json:"concurrency"`
+	Completed    int                  `json:"completed"`
+	Skipped      int                  `json:"skipped"`
+	Total        int                  `json:"total"`
+	StartedAt    time.Time            `json:"started_at"`
+	FinishedAt   *time.Time           `json:"finished_at,omitempty"`
+	Nodes        []*UpgradeNodeStatus `json:"nodes"`
+}
+
+// Only one rollout runs at a time; the last one stays visible once done
+var (
+	rollingUpgrade      *RollingUpgrade
+	rollingUpgradeMutex sync.Mutex
+)
+
+// StartRollingUpgrade validates the request and starts upgrading nodes in
+// the background. It fails if a rollout is already running.
+func (c *Cluster) StartRollingUpgrade(ctx context.Context, req RollingUpgradeRequest) (*RollingUpgrade, error) {
+	if req.DrainSeconds < 0 || req.DrainSeconds > maxUpgradePhaseSeconds {
+		return nil, fmt.Errorf("drain_seconds must be between 0 and %d", maxUpgradePhaseSeconds)
+	}
+	if req.NodeSeconds < 1 || req.NodeSeconds > maxUpgradePhaseSeconds {
+		return nil, fmt.Errorf("node_seconds must be between 1 and %d", maxUpgradePhaseSeconds)
+	}
+
+	c.mu.RLock()
+	var nodes []*Node
+	if len(req.Nodes) == 0 {
+		nodes = append(nodes, c.Nodes...)
+	}
+	seen := make(map[string]bool)
+	for _, id := range req.Nodes {
+		node := c.findNode(id)
+		if node == nil {
+			c.mu.RUnlock()
+			return nil, fmt.Errorf("node %q not found", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			nodes = append(nodes, node)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(nodes) == 0 {
+		return nil, errors.New("no nodes to upgrade")
+	}
+	if req.Concurrency < 1 || req.Concurrency > len(nodes) {
+		return nil, fmt.Errorf("concurrency must be between 1 and %d", len(nodes))
+	}
+
+	rollingUpgradeMutex.Lock()
+	defer rollingUpgradeMutex.Unlock()
+	if rollingUpgrade != nil && rollingUpgrade.State == "running" {
+		return nil, errUpgradeRunning
+	}
+
+	upgrade := &RollingUpgrade{
+		State:        "running",
+		DrainSeconds: req.DrainSeconds,
+		NodeSeconds:  req.NodeSeconds,
+		Concurrency:  req.Concurrency,
+		Total:        len(nodes),
+		StartedAt:    time.Now().UTC(),
+	}
+	for _, node := range nodes {
+		upgrade.Nodes = append(upgrade.Nodes, &UpgradeNodeStatus{Node: node.ID, Phase: upgradePending})
+	}
+	rollingUpgrade = upgrade
+
+	slog.InfoContext(ctx, "Rolling upgrade started",
+		"nodes", len(nodes),
+		"concurrency", req.Concurrency,
+		"drain_seconds", req.DrainSeconds,
+		"node_seconds", req.NodeSeconds,
+	)
+	go c.runRollingUpgrade(upgrade, nodes)
+	return upgrade, nil
+}
+
+// runRollingUpgrade upgrades the nodes with at most Concurrency in flight
+func (c *Cluster) runRollingUpgrade(upgrade *RollingUpgrade, nodes []*Node) {
+	slots := make(chan struct{}, upgrade.Concurrency)
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(status *UpgradeNodeStatus, node *Node) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			upgradeNode(upgrade, status, node)
+		}(upgrade.Nodes[i], node)
+	}
+	wg.Wait()
+
+	rollingUpgradeMutex.Lock()
+	finished := time.Now().UTC()
+	upgrade.State = "completed"
+	upgrade.FinishedAt = &finished
+	rollingUpgradeMutex.Unlock()
+
+	slog.Info("Rolling upgrade completed", "nodes", upgrade.Total, "duration", finished.Sub(upgrade.StartedAt).Round(time.Second).String())
+}
+
+// upgradeNode drains, takes down and restores one node. A node that was
+// already cordoned stays cordoned afterwards. A node that is down when its
+// turn comes, or goes down while draining, is skipped and left down, so the
+// rollout never brings back a node an operator drained.
+func upgradeNode(upgrade *RollingUpgrade, status *UpgradeNodeStatus, node *Node) {
+	node.mu.Lock()
+	if !node.IsUp {
+		node.mu.Unlock()
+		setUpgradePhase(upgrade, status, upgradeSkipped)
+		node.log.Info("Node down, skipped by rolling upgrade")
+		return
+	}
+	wasSchedulable := node.Schedulable
+	node.Schedulable = false
+	node.mu.Unlock()
+	setUpgradePhase(upgrade, status, upgradeDraining)
+	node.log.Info("Node draining for upgrade")
+	time.Sleep(time.Duration(upgrade.DrainSeconds) * time.Second)
+
+	node.mu.Lock()
+	if !node.IsUp {
+		node.Schedulable = wasSchedulable
+		node.mu.Unlock()
+		setUpgradePhase(upgrade, status, upgradeSkipped)
+		node.log.Info("Node went down while draining, skipped by rolling upgrade")
+		return
+	}
+	node.IsUp = false
+	node.mu.Unlock()
+	setUpgradePhase(upgrade, status, upgradeUpgrading)
+	node.log.Warn("Node down for upgrade", "duration_seconds", upgrade.NodeSeconds)
+	time.Sleep(time.Duration(upgrade.NodeSeconds) * time.Second)
+
+	node.mu.Lock()
+	node.IsUp = true
+	node.Schedulable = wasSchedulable
+	node.mu.Unlock()
+	setUpgradePhase(upgrade, status, upgradeDone)
+	node.log.Info("Node upgraded and resumed", "schedulable", wasSchedulable)
+}
+
+func setUpgradePhase(upgrade *RollingUpgrade, status *UpgradeNodeStatus, phase string) {
+	rollingUpgradeMutex.Lock()
+	defer rollingUpgradeMutex.Unlock()
+
+	now := time.Now().UTC()
+	status.Phase = phase
+	switch phase {
+	case upgradeDraining:
+		status.StartedAt = &now
+	case upgradeDone:
+		status.FinishedAt = &now
+		upgrade.Completed++
+	case upgradeSkipped:
+		status.FinishedAt = &now
+		upgrade.Skipped++
+	}
+}
+
+// HandleRollingUpgrade handles POST (start) and GET (progress) on
+// /api/cluster/rolling-upgrade
+func (c *Cluster) HandleRollingUpgrade(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		// An empty body starts a one-at-a-time rollout of every node
+		req := RollingUpgradeRequest{DrainSeconds: 2, NodeSeconds: 10, Concurrency: 1}
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if _, err := c.StartRollingUpgrade(r.Context(), req); err != nil {
+			if errors.Is(err, errUpgradeRunning) {
+				writeJSONError(w, http.StatusConflict, err.Error())
+			} else {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+			}
+			return
+		}
+		status = http.StatusAccepted
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	rollingUpgradeMutex.Lock()
+	defer rollingUpgradeMutex.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if rollingUpgrade == nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{"state": "idle"})
+		return
+	}
+	json.NewEncoder(w).Encode(rollingUpgrade)
+}
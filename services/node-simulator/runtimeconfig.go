@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RuntimeConfigView is the JSON shape of the simulator config
+type RuntimeConfigView struct {
+	GPUActiveProbability float64 `json:"gpu_active_probability"`
+	CPUBaseLoad          float64 `json:"cpu_base_load"`
+	MemoryBaseLoad       float64 `json:"memory_base_load"`
+	MemoryVariance       float64 `json:"memory_variance"`
+	TickIntervalMs       int64   `json:"tick_interval_ms"`
+
+	// Immutable after startup
+	GPUNodes    int    `json:"gpu_nodes"`
+	CPUNodes    int    `json:"cpu_nodes"`
+	MetricsPort string `json:"metrics_port"`
+}
+
+// RuntimeConfigUpdate is a partial update; omitted fields are left unchanged.
+// Immutable fields are accepted only if they match the current value.
+type RuntimeConfigUpdate struct {
+	GPUActiveProbability *float64 `json:"gpu_active_probability"`
+	CPUBaseLoad          *float64 `json:"cpu_base_load"`
+	MemoryBaseLoad       *float64 `json:"memory_base_load"`
+	MemoryVariance       *float64 `json:"memory_variance"`
+	TickIntervalMs       *int64   `json:"tick_interval_ms"`
+
+	GPUNodes    *int    `json:"gpu_nodes"`
+	CPUNodes    *int    `json:"cpu_nodes"`
+	MetricsPort *string `json:"metrics_port"`
+}
+
+const (
+	minTickInterval = 100 * time.Millisecond
+	maxTickInterval = time.Minute
+)
+
+func (c *Cluster) tickInterval() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.config.TickInterval
+}
+
+func (c *Cluster) configView() RuntimeConfigView {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return RuntimeConfigView{
+		GPUActiveProbability: c.config.GPUActiveProbability,
+		CPUBaseLoad:          c.config.CPUBaseLoad,
+		MemoryBaseLoad:       c.config.MemoryBaseLoad,
+		MemoryVariance:       c.config.MemoryVariance,
+		TickIntervalMs:       c.config.TickInterval.Milliseconds(),
+		GPUNodes:             c.config.GPUNodes,
+		CPUNodes:             c.config.CPUNodes,
+		MetricsPort:          c.config.MetricsPort,
+	}
+}
+
+// UpdateConfig validates and applies a runtime config update atomically
+func (c *Cluster) UpdateConfig(ctx context.Context, u RuntimeConfigUpdate) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if u.GPUNodes != nil && *u.GPUNodes != c.config.GPUNodes {
+		return fmt.Errorf("gpu_nodes is immutable at runtime")
+	}
+	if u.CPUNodes != nil && *u.CPUNodes != c.config.CPUNodes {
+		return fmt.Errorf("cpu_nodes is immutable at runtime")
+	}
+	if u.MetricsPort != nil && *u.MetricsPort != c.config.MetricsPort {
+		return fmt.Errorf("metrics_port is immutable at runtime")
+	}
+
+	next := c.config
+	if u.GPUActiveProbability != nil {
+		if *u.GPUActiveProbability < 0 || *u.GPUActiveProbability > 1 {
+			return fmt.Errorf("gpu_active_probability must be between 0 and 1")
+		}
+		next.GPUActiveProbability = *u.GPUActiveProbability
+	}
+	if u.CPUBaseLoad != nil {
+		if *u.CPUBaseLoad < 0 || *u.CPUBaseLoad > 100 {
+			return fmt.Errorf("cpu_base_load must be between 0 and 100")
+		}
+		next.CPUBaseLoad = *u.CPUBaseLoad
+	}
+	if u.MemoryBaseLoad != nil {
+		if *u.MemoryBaseLoad < 0 || *u.MemoryBaseLoad > 100 {
+			return fmt.Errorf("memory_base_load must be between 0 and 100")
+		}
+		next.MemoryBaseLoad = *u.MemoryBaseLoad
+	}
+	if u.MemoryVariance != nil {
+		if *u.MemoryVariance < 0 || *u.MemoryVariance > 100 {
+			return fmt.Errorf("memory_variance must be between 0 and 100")
+		}
+		next.MemoryVariance = *u.MemoryVariance
+	}
+	if u.TickIntervalMs != nil {
+		interval := time.Duration(*u.TickIntervalMs) * time.Millisecond
+		if interval < minTickInterval || interval > maxTickInterval {
+			return fmt.Errorf("tick_interval_ms must be between %d and %d",
+				minTickInterval.Milliseconds(), maxTickInterval.Milliseconds())
+		}
+		next.TickInterval = interval
+	}
+
+	c.config = next
+	slog.InfoContext(ctx, "Simulation config updated",
+		"gpu_active_probability", next.GPUActiveProbability,
+		"cpu_base_load", next.CPUBaseLoad,
+		"memory_base_load", next.MemoryBaseLoad,
+		"memory_variance", next.MemoryVariance,
+		"tick_interval", next.TickInterval,
+	)
+	return nil
+}
+
+// HandleConfigAPI handles GET and PUT /api/config
+func (c *Cluster) HandleConfigAPI(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var update RuntimeConfigUpdate
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&update); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if err := c.UpdateConfig(r.Context(), update); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(c.configView())
+}
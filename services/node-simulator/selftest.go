@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runSelfTest runs a single simulation tick and checks the node API and
+// metric registry, printing PASS or FAIL. It returns the process exit code.
+func runSelfTest(cluster *Cluster) (code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("SELFTEST FAIL: panic: %v\n", r)
+			code = 1
+		}
+	}()
+
+	cluster.simulateTick()
+
+	recorder := httptest.NewRecorder()
+	cluster.HandleNodesAPI(recorder, httptest.NewRequest("GET", "/api/nodes", nil))
+	if recorder.Code != http.StatusOK {
+		fmt.Printf("SELFTEST FAIL: nodes API: status %d\n", recorder.Code)
+		return 1
+	}
+	var payload struct {
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &payload); err != nil {
+		fmt.Printf("SELFTEST FAIL: nodes API: %v\n", err)
+		return 1
+	}
+	if payload.Total != len(cluster.Nodes) {
+		fmt.Printf("SELFTEST FAIL: nodes API: reported %d nodes, expected %d\n", payload.Total, len(cluster.Nodes))
+		return 1
+	}
+
+	if _, err := prometheus.DefaultGatherer.Gather(); err != nil {
+		fmt.Printf("SELFTEST FAIL: metric registration: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("SELFTEST PASS")
+	return 0
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"log/slog"
+	"math/rand"
+	"time"
+)
+
+// applyStartupStagger holds every node down at start and gives each an
+// online time within window, so pulse_node_up ramps up like a cluster
+// booting. Nodes come up in a random order at even intervals, the last one
+// at the end of the window.
+func applyStartupStagger(nodes []*Node, window time.Duration, rng *rand.Rand) {
+	if window <= 0 || len(nodes) == 0 {
+		return
+	}
+	start := time.Now()
+	for position, i := range rng.Perm(len(nodes)) {
+		node := nodes[i]
+		node.IsUp = false
+		node.OnlineAt = start.Add(window * time.Duration(position+1) / time.Duration(len(nodes)))
+	}
+	slog.Info("Node startup staggered", "nodes", len(nodes), "window", window.String())
+}
+
+// bringOnline marks a staggered node up once its online time has passed.
+// Caller must hold node.mu.
+func bringOnline(node *Node, now time.Time) {
+	if node.OnlineAt.IsZero() || now.Before(node.OnlineAt) {
+		return
+	}
+	node.IsUp = true
+	node.OnlineAt = time.Time{}
+	node.log.Info("Node online after startup stagger")
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// processStart anchors the uptime reported by system stats
+var processStart = time.Now()
+
+// SystemStats is a cheap runtime snapshot for spotting goroutine or memory
+// leaks without pulling a profile
+type SystemStats struct {
+	Goroutines    int        `json:"goroutines"`
+	HeapAlloc     uint64     `json:"heap_alloc_bytes"`
+	TotalAlloc    uint64     `json:"total_alloc_bytes"`
+	Sys           uint64     `json:"sys_bytes"`
+	HeapObjects   uint64     `json:"heap_objects"`
+	NumGC         uint32     `json:"num_gc"`
+	LastGC        *time.Time `json:"last_gc,omitempty"`
+	UptimeSeconds float64    `json:"uptime_seconds"`
+	StartedAt     time.Time  `json:"started_at"`
+	GoVersion     string     `json:"go_version"`
+}
+
+func readSystemStats() SystemStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := SystemStats{
+		Goroutines:    runtime.NumGoroutine(),
+		HeapAlloc:     mem.HeapAlloc,
+		TotalAlloc:    mem.TotalAlloc,
+		Sys:           mem.Sys,
+		HeapObjects:   mem.HeapObjects,
+		NumGC:         mem.NumGC,
+		UptimeSeconds: math.Round(time.Since(processStart).Seconds()),
+		StartedAt:     processStart.UTC(),
+		GoVersion:     runtime.Version(),
+	}
+	if mem.LastGC > 0 {
+		lastGC := time.Unix(0, int64(mem.LastGC)).UTC()
+		stats.LastGC = &lastGC
+	}
+	return stats
+}
+
+// HandleSystemStats serves goroutine, memory and uptime stats
+func HandleSystemStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(readSystemStats())
+}
@@ -0,0 +1,40 @@
+package main
+
+import "math/rand"
+
+// WorkloadProfile describes what bounds a GPU's simulated workload. It
+// splits the GPU's activity between SM (compute) utilization and memory
+// bandwidth utilization, so compute-bound and memory-bound GPUs can be
+// told apart by more than a single utilization signal.
+type WorkloadProfile string
+
+const (
+	WorkloadCompute  WorkloadProfile = "compute"
+	WorkloadMemory   WorkloadProfile = "memory"
+	WorkloadBalanced WorkloadProfile = "balanced"
+)
+
+// workloadProfiles lists the profiles in the order they are drawn from
+var workloadProfiles = []WorkloadProfile{WorkloadCompute, WorkloadMemory, WorkloadBalanced}
+
+// workloadShares scales a GPU's activity level into SM utilization and
+// memory bandwidth utilization. Balanced keeps the full activity as SM
+// utilization, as before profiles existed.
+var workloadShares = map[WorkloadProfile]struct{ sm, memBandwidth float64 }{
+	WorkloadCompute:  {sm: 1.0, memBandwidth: 0.35},
+	WorkloadMemory:   {sm: 0.45, memBandwidth: 1.3},
+	WorkloadBalanced: {sm: 1.0, memBandwidth: 0.8},
+}
+
+// randomWorkloadProfile picks a GPU's profile, evenly across the three
+func randomWorkloadProfile(rng *rand.Rand) WorkloadProfile {
+	return workloadProfiles[rng.Intn(len(workloadProfiles))]
+}
+
+// memBandwidthFor returns the memory bandwidth utilization that goes with a
+// GPU's SM utilization under its profile, with a little noise
+func memBandwidthFor(profile WorkloadProfile, smUtil float64) float64 {
+	shares := workloadShares[profile]
+	activity := smUtil / shares.sm
+	return clamp(activity*shares.memBandwidth+rand.NormFloat64()*3, 0, 100)
+}